@@ -0,0 +1,81 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: leitura do manifest.json gerado por "minifyx build" (nome lógico →
+//          nome com hash), para usar nos templates:
+//
+//              m, _ := assetmanifest.Load("static/manifest.json")
+//              m.Prefix = "/static/"
+//              tmpl := template.New("").Funcs(m.FuncMap())
+//              // {{ asset "js/app.js" }} → /static/js/app.3f9a1c.min.js
+// License: MIT
+
+package assetmanifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Manifest associa os nomes lógicos dos assets (ex: "js/app.js") aos nomes com
+// hash gerados (ex: "js/app.3f9a1c.min.js").
+type Manifest struct {
+    // Prefix é acrescentado ao início dos caminhos devolvidos por URL e pela
+    // função "asset" dos templates (ex: "/static/").
+    Prefix string
+
+    entries map[string]string
+}
+
+// Load lê o manifest de um ficheiro.
+func Load(path string) (*Manifest, error) {
+    b, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return Parse(b)
+}
+
+// LoadFS lê o manifest de um fs.FS (ex: embed.FS).
+func LoadFS(fsys fs.FS, name string) (*Manifest, error) {
+    b, err := fs.ReadFile(fsys, name)
+    if err != nil {
+        return nil, err
+    }
+    return Parse(b)
+}
+
+// Parse interpreta o conteúdo de um manifest.json.
+func Parse(data []byte) (*Manifest, error) {
+    m := &Manifest{}
+    if err := json.Unmarshal(data, &m.entries); err != nil {
+        return nil, fmt.Errorf("manifest inválido: %w", err)
+    }
+    return m, nil
+}
+
+// Lookup devolve o nome com hash de name (sem Prefix).
+func (m *Manifest) Lookup(name string) (string, bool) {
+    hashed, ok := m.entries[strings.TrimPrefix(name, "/")]
+    return hashed, ok
+}
+
+// URL devolve Prefix + nome com hash de name, ou erro se name não estiver no
+// manifest (um asset em falta deve falhar ao gerar a página, não no browser).
+func (m *Manifest) URL(name string) (string, error) {
+    hashed, ok := m.Lookup(name)
+    if !ok {
+        return "", fmt.Errorf("asset %q não existe no manifest", name)
+    }
+    return m.Prefix + hashed, nil
+}
+
+// FuncMap devolve as funções para templates: {{ asset "js/app.js" }}.
+func (m *Manifest) FuncMap() template.FuncMap {
+    return template.FuncMap{
+        "asset": m.URL,
+    }
+}
@@ -0,0 +1,70 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a leitura do manifest.json e a função asset dos templates
+// License: MIT
+
+package assetmanifest
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const manifestJSON = `{
+  "css/site.css": "css/site.1a2b3c.min.css",
+  "js/app.js": "js/app.3f9a1c.min.js"
+}`
+
+func TestManifestURL(t *testing.T) {
+    m, err := Parse([]byte(manifestJSON))
+    if err != nil {
+        t.Fatal(err)
+    }
+    m.Prefix = "/static/"
+
+    tests := []struct {
+        name     string
+        expected string
+    }{
+        {"js/app.js", "/static/js/app.3f9a1c.min.js"},
+        {"/css/site.css", "/static/css/site.1a2b3c.min.css"},
+    }
+    for _, tt := range tests {
+        got, err := m.URL(tt.name)
+        if err != nil || got != tt.expected {
+            t.Errorf("%s: got %q, %v, want %q", tt.name, got, err, tt.expected)
+        }
+    }
+    if _, err := m.URL("js/falta.js"); err == nil {
+        t.Error("asset em falta: esperado erro")
+    }
+}
+
+func TestManifestTemplate(t *testing.T) {
+    m, err := LoadFS(fstest.MapFS{"manifest.json": {Data: []byte(manifestJSON)}}, "manifest.json")
+    if err != nil {
+        t.Fatal(err)
+    }
+    tmpl := template.Must(template.New("").Funcs(m.FuncMap()).Parse(`<script src="{{ asset "js/app.js" }}"></script>`))
+
+    var b strings.Builder
+    if err := tmpl.Execute(&b, nil); err != nil {
+        t.Fatal(err)
+    }
+    if expected := `<script src="js/app.3f9a1c.min.js"></script>`; b.String() != expected {
+        t.Errorf("got %q, want %q", b.String(), expected)
+    }
+
+    tmpl = template.Must(template.New("").Funcs(m.FuncMap()).Parse(`{{ asset "x.js" }}`))
+    if err := tmpl.Execute(&b, nil); err == nil {
+        t.Error("asset em falta: esperado erro do template")
+    }
+}
+
+func TestParseInvalid(t *testing.T) {
+    if _, err := Parse([]byte(`["a"]`)); err == nil {
+        t.Error("esperado erro")
+    }
+}
@@ -0,0 +1,187 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: subcomando "minifyx build": minifica uma árvore de assets para uma pasta
+//          de saída com o hash do conteúdo no nome (app.js → app.3f9a1c.min.js) e
+//          escreve um manifest.json com o nome lógico → nome com hash. Os ficheiros
+//          de tipos não suportados (imagens, fontes ...) são copiados, também com
+//          hash (logo.png → logo.9b2e4d.png), e também com o nome original, para
+//          que url(...) no CSS e src/href no HTML continuem a encontrá-los. O
+//          mesmo input dá sempre o mesmo output, por isso pode correr em go:generate:
+//
+//              //go:generate go run github.com/pinjoa/minifyx/cmd/minifyx build -o static assets
+//
+//          O manifest é lido pelo pacote assetmanifest (ex: em html/template).
+// License: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// runBuild executa o subcomando build e devolve o código de saída.
+func runBuild(args []string, opts *minifier.Options, parallel int, compress compressConfig) int {
+    var (
+        outDir       string
+        manifestName string
+        hashLen      int
+    )
+    fset := flag.NewFlagSet("build", flag.ContinueOnError)
+    fset.StringVar(&outDir, "o", "dist", "Pasta de saída")
+    fset.StringVar(&manifestName, "manifest", "manifest.json", "Nome do manifest (relativo à pasta de saída)")
+    fset.IntVar(&hashLen, "hash-len", 6, "Número de caracteres hexadecimais do hash nos nomes")
+    fset.IntVar(&parallel, "parallel", parallel, "Número de goroutines em paralelo")
+    fset.Usage = func() {
+        fmt.Fprintln(fset.Output(), "Uso: minifyx [opções de minificação] build [-o dist] [-manifest manifest.json] <pasta>")
+        fset.PrintDefaults()
+    }
+    if err := fset.Parse(args); err != nil {
+        return exitUsage
+    }
+    if fset.NArg() != 1 {
+        fset.Usage()
+        return exitUsage
+    }
+    if hashLen < 4 || hashLen > sha256.Size*2 {
+        fmt.Fprintln(os.Stderr, "-hash-len tem de estar entre 4 e 64")
+        return exitUsage
+    }
+    srcDir := filepath.Clean(fset.Arg(0))
+
+    files, err := assetFiles(srcDir, outDir)
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "Erro:", err)
+        return exitFailed
+    }
+
+    results := runWorkers(context.Background(), files, parallel, func(path string) (string, error) {
+        if minifier.DetectType(path) == minifier.ERROR {
+            b, err := os.ReadFile(path)
+            return string(b), err
+        }
+        return minifier.MinifyFile(path, opts)
+    })
+
+    manifest := make(map[string]string, len(files))
+    var failures []failure
+    totalSize := 0
+    totalCompressed := map[string]int{} // ".gz" → soma dos tamanhos
+    for r := range results {
+        if r.err != nil {
+            fmt.Fprintln(os.Stderr, "Erro:", r.path, r.err)
+            failures = append(failures, failure{path: r.path, err: r.err})
+            continue
+        }
+        rel, _ := filepath.Rel(srcDir, r.path)
+        minified := minifier.DetectType(r.path) != minifier.ERROR
+        hashed := hashedName(rel, r.out, hashLen, minified)
+        dest := filepath.Join(outDir, hashed)
+        if err := os.MkdirAll(filepath.Dir(dest), 0755); err == nil {
+            err = os.WriteFile(dest, []byte(r.out), 0644)
+        }
+        if err == nil && !minified {
+            // o CSS e o HTML referem imagens e fontes pelo nome original
+            // (url(../img/logo.png)): a cópia sem hash mantém esses caminhos válidos
+            err = os.WriteFile(filepath.Join(outDir, rel), []byte(r.out), 0644)
+        }
+        if err != nil {
+            fmt.Fprintln(os.Stderr, "Erro a escrever:", dest, err)
+            failures = append(failures, failure{path: dest, err: err})
+            continue
+        }
+        compressed, err := writeCompressed(dest, []byte(r.out), compress)
+        if err != nil {
+            fmt.Fprintln(os.Stderr, "Erro a comprimir:", dest, err)
+            failures = append(failures, failure{path: dest, err: err})
+            continue
+        }
+        totalSize += len(r.out)
+        for _, c := range compressed {
+            // os ficheiros não comprimidos contam com o tamanho original
+            if c.size == 0 {
+                totalCompressed[c.ext] += len(r.out)
+            } else {
+                totalCompressed[c.ext] += c.size
+            }
+        }
+        manifest[filepath.ToSlash(rel)] = filepath.ToSlash(hashed)
+    }
+    if len(failures) > 0 {
+        // sem manifest: não apontar para um conjunto de assets incompleto
+        printSummary(os.Stderr, failures, len(files), 0)
+        fmt.Fprintln(os.Stderr, "manifest não escrito")
+        return exitCode(failures, 0)
+    }
+
+    // encoding/json ordena as chaves: o manifest é sempre igual para o mesmo input
+    data, _ := json.MarshalIndent(manifest, "", "  ")
+    manifestPath := filepath.Join(outDir, manifestName)
+    if err := os.MkdirAll(filepath.Dir(manifestPath), 0755); err == nil {
+        err = os.WriteFile(manifestPath, append(data, '\n'), 0644)
+    }
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "Erro a escrever:", manifestPath, err)
+        return exitFailed
+    }
+    fmt.Printf("%d ficheiro(s) → %s (%s), %d B", len(manifest), outDir, manifestPath, totalSize)
+    for _, ext := range []string{".gz", ".deflate"} {
+        if size, ok := totalCompressed[ext]; ok {
+            fmt.Printf(", %s %d B", strings.TrimPrefix(ext, "."), size)
+        }
+    }
+    fmt.Println()
+    return exitOK
+}
+
+// assetFiles devolve os ficheiros de srcDir (por ordem), sem ficheiros/pastas
+// escondidos e sem a pasta de saída, se estiver dentro de srcDir.
+func assetFiles(srcDir, outDir string) ([]string, error) {
+    absOut, _ := filepath.Abs(outDir)
+    var files []string
+    err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        if path != srcDir && strings.HasPrefix(d.Name(), ".") {
+            if d.IsDir() {
+                return filepath.SkipDir
+            }
+            return nil
+        }
+        if d.IsDir() {
+            if abs, _ := filepath.Abs(path); abs == absOut {
+                return filepath.SkipDir
+            }
+            return nil
+        }
+        if d.Type().IsRegular() {
+            files = append(files, path)
+        }
+        return nil
+    })
+    return files, err
+}
+
+// hashedName acrescenta ao nome o hash do conteúdo: js/app.js → js/app.3f9a1c.min.js
+// (minificado) ou img/logo.png → img/logo.9b2e4d.png.
+func hashedName(rel, content string, hashLen int, minified bool) string {
+    sum := sha256.Sum256([]byte(content))
+    hash := hex.EncodeToString(sum[:])[:hashLen]
+    ext := filepath.Ext(rel)
+    base := strings.TrimSuffix(rel, ext)
+    if minified {
+        return strings.TrimSuffix(base, ".min") + "." + hash + ".min" + ext
+    }
+    return base + "." + hash + ext
+}
@@ -0,0 +1,184 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o subcomando build (nomes com hash, ficheiros
+//          incluídos e manifest.json)
+// License: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// writeFiles cria em dir os ficheiros de files (caminho relativo → conteúdo).
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+    t.Helper()
+    for name, content := range files {
+        path := filepath.Join(dir, filepath.FromSlash(name))
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func TestHashedName(t *testing.T) {
+    // sha256("x") = 2d711642...
+    tests := []struct {
+        rel      string
+        minified bool
+        expected string
+    }{
+        {"js/app.js", true, "js/app.2d7116.min.js"},
+        {"app.min.js", true, "app.2d7116.min.js"},
+        {"img/logo.png", false, "img/logo.2d7116.png"},
+        {"LICENSE", false, "LICENSE.2d7116"},
+    }
+    for _, tt := range tests {
+        if got := hashedName(tt.rel, "x", 6, tt.minified); got != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.rel, got, tt.expected)
+        }
+    }
+    if got := hashedName("a.css", "x", 10, true); got != "a.2d711642b7.min.css" {
+        t.Errorf("hash-len 10: got %q", got)
+    }
+}
+
+func TestAssetFiles(t *testing.T) {
+    src := t.TempDir()
+    writeFiles(t, src, map[string]string{
+        "a.css":       "a{}",
+        "sub/b.js":    "b()",
+        ".env":        "segredo",
+        ".git/config": "x",
+        "dist/c.js":   "gerado",
+    })
+    got, err := assetFiles(src, filepath.Join(src, "dist"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []string{filepath.Join(src, "a.css"), filepath.Join(src, "sub", "b.js")}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("got %q, want %q", got, want)
+    }
+}
+
+func TestRunBuild(t *testing.T) {
+    src := t.TempDir()
+    out := filepath.Join(t.TempDir(), "static")
+    writeFiles(t, src, map[string]string{
+        "js/app.js": "var a = 1;\n",
+        "style.css": "body {\n  color: red;\n}\n",
+        "logo.png":  "\x89PNG",
+    })
+    if code := runBuild([]string{"-o", out, src}, minifier.DefaultOptions(), 2, compressConfig{}); code != exitOK {
+        t.Fatalf("código %d", code)
+    }
+
+    data, err := os.ReadFile(filepath.Join(out, "manifest.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var manifest map[string]string
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        t.Fatal(err)
+    }
+    if len(manifest) != 3 {
+        t.Fatalf("manifest: %v", manifest)
+    }
+    for logical, want := range map[string]string{
+        "js/app.js": "var a=1;",
+        "style.css": "body{color:red;}",
+        "logo.png":  "\x89PNG",
+    } {
+        hashed, ok := manifest[logical]
+        if !ok {
+            t.Errorf("%s: não está no manifest", logical)
+            continue
+        }
+        got, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(hashed)))
+        if err != nil {
+            t.Errorf("%s: %v", logical, err)
+            continue
+        }
+        if string(got) != want {
+            t.Errorf("%s (%s): got %q, want %q", logical, hashed, got, want)
+        }
+    }
+    if filepath.Ext(manifest["js/app.js"]) != ".js" || filepath.Dir(manifest["js/app.js"]) != "js" {
+        t.Errorf("nome com hash: %q", manifest["js/app.js"])
+    }
+}
+
+// o CSS continua a encontrar as imagens e fontes que refere pelo nome original
+func TestRunBuildAssetReferences(t *testing.T) {
+    src := t.TempDir()
+    out := filepath.Join(t.TempDir(), "static")
+    writeFiles(t, src, map[string]string{
+        "css/app.css":      "body {\n  background: url(\"../img/bg.png\");\n}\n",
+        "img/bg.png":       "\x89PNG",
+        "fonts/sans.woff2": "wOF2",
+    })
+    if code := runBuild([]string{"-o", out, src}, minifier.DefaultOptions(), 1, compressConfig{}); code != exitOK {
+        t.Fatalf("código %d", code)
+    }
+
+    data, err := os.ReadFile(filepath.Join(out, "manifest.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    var manifest map[string]string
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        t.Fatal(err)
+    }
+    css := filepath.Join(out, filepath.FromSlash(manifest["css/app.css"]))
+    got, err := os.ReadFile(css)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(got) != `body{background:url("../img/bg.png");}` {
+        t.Fatalf("css: %q", got)
+    }
+    // o url(...) resolvido a partir do CSS gerado
+    img, err := os.ReadFile(filepath.Join(filepath.Dir(css), "..", "img", "bg.png"))
+    if err != nil || string(img) != "\x89PNG" {
+        t.Errorf("imagem referida pelo CSS: %q, %v", img, err)
+    }
+    for _, logical := range []string{"img/bg.png", "fonts/sans.woff2"} {
+        if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(manifest[logical]))); err != nil {
+            t.Errorf("cópia com hash de %s: %v", logical, err)
+        }
+        if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(logical))); err != nil {
+            t.Errorf("cópia com o nome original de %s: %v", logical, err)
+        }
+    }
+}
+
+// com erros não há manifest (não apontar para assets incompletos)
+func TestRunBuildFailureNoManifest(t *testing.T) {
+    src := t.TempDir()
+    out := filepath.Join(t.TempDir(), "static")
+    writeFiles(t, src, map[string]string{
+        "a.css":    "a{}",
+        "feed.xml": "<a><b></a>",
+    })
+    opts := minifier.DefaultOptions()
+    opts.XMLValidate = true
+    if code := runBuild([]string{"-o", out, src}, opts, 1, compressConfig{}); code != exitValidation {
+        t.Errorf("código %d, esperado %d", code, exitValidation)
+    }
+    if _, err := os.Stat(filepath.Join(out, "manifest.json")); !os.IsNotExist(err) {
+        t.Errorf("manifest escrito apesar do erro (%v)", err)
+    }
+    if code := runBuild([]string{"-o", out}, opts, 1, compressConfig{}); code != exitUsage {
+        t.Errorf("sem pasta: código %d", code)
+    }
+}
@@ -0,0 +1,74 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: modo -check (CI): minifica em memória e compara com os ficheiros gerados
+//          que já existem (mesmos nomes que seriam escritos), sem escrever nada.
+//          Para cada diferença mostra um resumo: tamanhos, posição da primeira
+//          diferença e o texto à volta nos dois lados.
+// License: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// bytes de contexto mostrados de cada lado da primeira diferença
+const checkContext = 30
+
+// checkOutput compara o conteúdo esperado want com o ficheiro dest. ok = igual;
+// se não, summary descreve a diferença.
+func checkOutput(dest, want string) (ok bool, summary string, err error) {
+    b, err := os.ReadFile(dest)
+    if os.IsNotExist(err) {
+        return false, "Em falta: " + dest, nil
+    }
+    if err != nil {
+        return false, "", err
+    }
+    got := string(b)
+    if got == want {
+        return true, "", nil
+    }
+
+    i := 0
+    for i < len(got) && i < len(want) && got[i] == want[i] {
+        i++
+    }
+    // posição no início do carácter, para não cortar UTF-8 ao meio
+    for i > 0 && i < len(want) && !utf8.RuneStart(want[i]) {
+        i--
+    }
+    line := strings.Count(want[:i], "\n") + 1
+    lineStart := strings.LastIndexByte(want[:i], '\n') + 1
+    col := utf8.RuneCountInString(want[lineStart:i]) + 1
+
+    var sb strings.Builder
+    fmt.Fprintf(&sb, "Desatualizado: %s (esperado %d B, existe %d B; primeira diferença no byte %d, linha %d, coluna %d)\n",
+        dest, len(want), len(got), i, line, col)
+    fmt.Fprintf(&sb, "  esperado: %s\n", diffContext(want, i))
+    fmt.Fprintf(&sb, "  existe:   %s", diffContext(got, i))
+    return false, sb.String(), nil
+}
+
+// diffContext devolve o texto de s à volta de i, entre aspas (com "…" se cortado).
+func diffContext(s string, i int) string {
+    start := max(i-checkContext, 0)
+    end := min(i+checkContext, len(s))
+    for start > 0 && !utf8.RuneStart(s[start]) {
+        start--
+    }
+    for end < len(s) && !utf8.RuneStart(s[end]) {
+        end++
+    }
+    ctx := fmt.Sprintf("%q", s[start:end])
+    if start > 0 {
+        ctx = "…" + ctx
+    }
+    if end < len(s) {
+        ctx += "…"
+    }
+    return ctx
+}
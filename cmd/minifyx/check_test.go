@@ -0,0 +1,68 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o modo -check (comparação com os ficheiros gerados)
+// License: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckOutput(t *testing.T) {
+    dir := t.TempDir()
+    dest := filepath.Join(dir, "site.min.css")
+    os.WriteFile(dest, []byte("body{color:red;}"), 0644)
+
+    ok, summary, err := checkOutput(dest, "body{color:red;}")
+    if !ok || summary != "" || err != nil {
+        t.Errorf("igual: ok=%v summary=%q err=%v", ok, summary, err)
+    }
+
+    ok, summary, err = checkOutput(filepath.Join(dir, "app.min.js"), "x")
+    if ok || err != nil || summary != "Em falta: "+filepath.Join(dir, "app.min.js") {
+        t.Errorf("em falta: ok=%v summary=%q err=%v", ok, summary, err)
+    }
+
+    ok, summary, err = checkOutput(dest, "body{color:blue;}")
+    if ok || err != nil {
+        t.Fatalf("diferente: ok=%v err=%v", ok, err)
+    }
+    want := "Desatualizado: " + dest + " (esperado 17 B, existe 16 B; primeira diferença no byte 11, linha 1, coluna 12)\n" +
+        "  esperado: \"body{color:blue;}\"\n" +
+        "  existe:   \"body{color:red;}\""
+    if summary != want {
+        t.Errorf("got:\n%s\nwant:\n%s", summary, want)
+    }
+
+    // a pasta não é um ficheiro: erro, não "desatualizado"
+    if _, _, err := checkOutput(dir, "x"); err == nil {
+        t.Error("esperado erro a ler uma pasta")
+    }
+}
+
+// linha e coluna contam caracteres (não bytes) e a posição não corta UTF-8
+func TestCheckOutputPosition(t *testing.T) {
+    dest := filepath.Join(t.TempDir(), "a.min.html")
+    os.WriteFile(dest, []byte("<p>\nolá é</p>"), 0644)
+
+    _, summary, _ := checkOutput(dest, "<p>\nolá è</p>")
+    if !strings.Contains(summary, "byte 9, linha 2, coluna 5)") {
+        t.Errorf("got %q", summary)
+    }
+}
+
+func TestDiffContext(t *testing.T) {
+    s := strings.Repeat("a", 50) + "X" + strings.Repeat("b", 50)
+    got := diffContext(s, 50)
+    want := "…\"" + strings.Repeat("a", 30) + "X" + strings.Repeat("b", 29) + "\"…"
+    if got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+    if got := diffContext("abc", 1); got != `"abc"` {
+        t.Errorf("curto: got %s", got)
+    }
+}
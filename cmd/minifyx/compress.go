@@ -0,0 +1,138 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: versões pré-comprimidas dos ficheiros gerados (app.min.js.gz para o
+//          gzip_static do nginx, app.min.js.deflate com zlib), para não ser
+//          preciso um script de compressão à parte. Ficheiros pequenos ou em que a
+//          compressão não ajuda não são comprimidos (e uma versão antiga que
+//          exista é apagada, para o servidor não a usar).
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// compressConfig são as opções de compressão da linha de comandos.
+type compressConfig struct {
+    gzip    bool
+    deflate bool
+    level   int // compress/flate: 1 (rápido) … 9 (melhor)
+    minSize int // abaixo disto (bytes) não comprime
+    dryRun  bool // só calcula os tamanhos, sem escrever nem apagar
+}
+
+func (c compressConfig) enabled() bool {
+    return c.gzip || c.deflate
+}
+
+// compressedFile é o resultado de uma versão comprimida (size = 0 se não foi escrita).
+type compressedFile struct {
+    ext  string
+    size int
+}
+
+// writeCompressed escreve as versões comprimidas de dest (conteúdo data) e
+// devolve o tamanho de cada uma.
+func writeCompressed(dest string, data []byte, cfg compressConfig) ([]compressedFile, error) {
+    var files []compressedFile
+    if cfg.gzip {
+        f, err := writeCompressedSibling(dest, ".gz", data, cfg, func(w io.Writer) (io.WriteCloser, error) {
+            return gzip.NewWriterLevel(w, cfg.level)
+        })
+        if err != nil {
+            return files, err
+        }
+        files = append(files, f)
+    }
+    if cfg.deflate {
+        f, err := writeCompressedSibling(dest, ".deflate", data, cfg, func(w io.Writer) (io.WriteCloser, error) {
+            return zlib.NewWriterLevel(w, cfg.level)
+        })
+        if err != nil {
+            return files, err
+        }
+        files = append(files, f)
+    }
+    return files, nil
+}
+
+func writeCompressedSibling(dest, ext string, data []byte, cfg compressConfig, newWriter func(io.Writer) (io.WriteCloser, error)) (compressedFile, error) {
+    path := dest + ext
+    if len(data) < cfg.minSize {
+        return compressedFile{ext: ext}, removeStale(path, cfg)
+    }
+    var b bytes.Buffer
+    zw, err := newWriter(&b)
+    if err != nil {
+        return compressedFile{ext: ext}, err
+    }
+    zw.Write(data)
+    if err := zw.Close(); err != nil {
+        return compressedFile{ext: ext}, err
+    }
+    if b.Len() >= len(data) {
+        // não compensa: o servidor envia o original
+        return compressedFile{ext: ext}, removeStale(path, cfg)
+    }
+    if cfg.dryRun {
+        return compressedFile{ext: ext, size: b.Len()}, nil
+    }
+    if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+        return compressedFile{ext: ext}, err
+    }
+    return compressedFile{ext: ext, size: b.Len()}, nil
+}
+
+// gzipSize é o tamanho de data comprimido com gzip (para o relatório).
+func gzipSize(data []byte, level int) int {
+    var b bytes.Buffer
+    zw, _ := gzip.NewWriterLevel(&b, level)
+    zw.Write(data)
+    zw.Close()
+    return b.Len()
+}
+
+// removeStale apaga uma versão comprimida antiga (de um build anterior).
+func removeStale(path string, cfg compressConfig) error {
+    if cfg.dryRun {
+        return nil
+    }
+    if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    return nil
+}
+
+// compressedSize devolve o tamanho da versão ext (0 se não foi escrita).
+func compressedSize(files []compressedFile, ext string) int {
+    for _, f := range files {
+        if f.ext == ext {
+            return f.size
+        }
+    }
+    return 0
+}
+
+// formatCompressed descreve os tamanhos para o relatório: " (gz 812 B, deflate 800 B)".
+func formatCompressed(files []compressedFile) string {
+    if len(files) == 0 {
+        return ""
+    }
+    parts := make([]string, len(files))
+    for i, f := range files {
+        name := strings.TrimPrefix(f.ext, ".")
+        if f.size == 0 {
+            parts[i] = name + " -"
+        } else {
+            parts[i] = fmt.Sprintf("%s %d B", name, f.size)
+        }
+    }
+    return " (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,153 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para as versões pré-comprimidas (.gz/.deflate)
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompressed(t *testing.T) {
+    dest := filepath.Join(t.TempDir(), "app.min.js")
+    data := []byte(strings.Repeat("var a=1;", 100))
+    cfg := compressConfig{gzip: true, deflate: true, level: 9, minSize: 256}
+
+    files, err := writeCompressed(dest, data, cfg)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(files) != 2 || files[0].ext != ".gz" || files[1].ext != ".deflate" {
+        t.Fatalf("got %+v", files)
+    }
+
+    gz, err := os.ReadFile(dest + ".gz")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(gz) != files[0].size {
+        t.Errorf("gz: tamanho %d, devolvido %d", len(gz), files[0].size)
+    }
+    zr, err := gzip.NewReader(bytes.NewReader(gz))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got, _ := io.ReadAll(zr); !bytes.Equal(got, data) {
+        t.Error("gz: conteúdo diferente")
+    }
+
+    zl, err := os.ReadFile(dest + ".deflate")
+    if err != nil {
+        t.Fatal(err)
+    }
+    fr, err := zlib.NewReader(bytes.NewReader(zl))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got, _ := io.ReadAll(fr); !bytes.Equal(got, data) {
+        t.Error("deflate: conteúdo diferente")
+    }
+    if got := formatCompressed(files); !strings.HasPrefix(got, " (gz ") || !strings.Contains(got, ", deflate ") {
+        t.Errorf("formatCompressed: %q", got)
+    }
+}
+
+// abaixo de -compress-min-size, ou se não ficar menor, não comprime e apaga a
+// versão de um build anterior
+func TestWriteCompressedSkipsAndRemovesStale(t *testing.T) {
+    dir := t.TempDir()
+    cfg := compressConfig{gzip: true, level: 9, minSize: 256}
+    tests := []struct {
+        name string
+        data []byte
+    }{
+        {"pequeno", []byte(strings.Repeat("a", 255))},
+        {"não fica menor", randomBytes(1024)},
+    }
+    for _, tt := range tests {
+        dest := filepath.Join(dir, "f.min.js")
+        if err := os.WriteFile(dest+".gz", []byte("antigo"), 0644); err != nil {
+            t.Fatal(err)
+        }
+        files, err := writeCompressed(dest, tt.data, cfg)
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if len(files) != 1 || files[0].size != 0 {
+            t.Errorf("%s: got %+v", tt.name, files)
+        }
+        if _, err := os.Stat(dest + ".gz"); !os.IsNotExist(err) {
+            t.Errorf("%s: .gz antigo não foi apagado", tt.name)
+        }
+        if got := formatCompressed(files); got != " (gz -)" {
+            t.Errorf("%s: formatCompressed %q", tt.name, got)
+        }
+    }
+
+    // no limite já comprime
+    dest := filepath.Join(dir, "g.min.js")
+    files, _ := writeCompressed(dest, []byte(strings.Repeat("a", 256)), cfg)
+    if files[0].size == 0 {
+        t.Error("256 B com -compress-min-size 256 devia ser comprimido")
+    }
+}
+
+// com dryRun, os tamanhos são calculados sem escrever nem apagar nada
+func TestWriteCompressedDryRun(t *testing.T) {
+    dir := t.TempDir()
+    dest := filepath.Join(dir, "a.min.css")
+    os.WriteFile(dest+".deflate", []byte("antigo"), 0644)
+    cfg := compressConfig{gzip: true, deflate: true, level: 9, minSize: 1000, dryRun: true}
+
+    files, err := writeCompressed(dest, []byte(strings.Repeat("a{}", 400)), cfg)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if files[0].size == 0 {
+        t.Errorf("got %+v", files)
+    }
+    if _, err := os.Stat(dest + ".gz"); !os.IsNotExist(err) {
+        t.Error("dry-run escreveu o .gz")
+    }
+    writeCompressed(dest, []byte("a{}"), cfg)
+    if _, err := os.Stat(dest + ".deflate"); err != nil {
+        t.Error("dry-run apagou o .deflate antigo")
+    }
+    if formatCompressed(nil) != "" {
+        t.Error("formatCompressed(nil) devia ser vazio")
+    }
+}
+
+// randomBytes devolve bytes pseudo-aleatórios (incompressíveis), sempre iguais.
+func randomBytes(n int) []byte {
+    b := make([]byte, n)
+    x := uint32(2463534242)
+    for i := range b {
+        x ^= x << 13
+        x ^= x >> 17
+        x ^= x << 5
+        b[i] = byte(x)
+    }
+    return b
+}
+
+func TestCompressedSize(t *testing.T) {
+    files := []compressedFile{{ext: ".gz", size: 120}, {ext: ".deflate"}}
+    if got := compressedSize(files, ".gz"); got != 120 {
+        t.Errorf(".gz: %d", got)
+    }
+    if got := compressedSize(files, ".deflate"); got != 0 {
+        t.Errorf(".deflate não escrito: %d", got)
+    }
+    if got := compressedSize(nil, ".gz"); got != 0 {
+        t.Errorf("sem compressão: %d", got)
+    }
+}
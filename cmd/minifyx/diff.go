@@ -0,0 +1,88 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: modo -diff: mostra o que uma execução mudaria, como diff unificado entre
+//          o ficheiro gerado que já existe (ou, se ainda não existir, o original) e
+//          o resultado novo. Como o output minificado fica quase todo numa linha,
+//          os dois lados são formatados (pretty-print) antes de comparar, para o
+//          diff mostrar as alterações linha a linha (se a formatação esconder a
+//          diferença, o diff é do texto tal como está).
+// License: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pinjoa/minifyx/internal/diff"
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// linhas de contexto à volta de cada alteração
+const diffContextLines = 3
+
+// printDiff escreve no stdout o diff de dest (ou de path) para out.
+func printDiff(path, dest, out, forceType string, opts *minifier.Options) {
+    d, err := unifiedDiff(path, dest, out, forceType, opts)
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "Erro:", path, err)
+        return
+    }
+    if d == "" {
+        // só o stdout leva o diff (para poder ser guardado como patch)
+        fmt.Fprintln(os.Stderr, "Sem diferenças:", dest)
+        return
+    }
+    fmt.Print(d)
+}
+
+// unifiedDiff devolve o diff de dest (ou, se não existir, de path) para out; ""
+// se forem iguais.
+func unifiedDiff(path, dest, out, forceType string, opts *minifier.Options) (string, error) {
+    oldName := dest
+    old, err := os.ReadFile(dest)
+    if err != nil {
+        // ainda não há ficheiro gerado: comparar com o original
+        oldName = path + " (original)"
+        old, err = os.ReadFile(path)
+        if err != nil {
+            return "", err
+        }
+    }
+    if string(old) == out {
+        return "", nil
+    }
+
+    // sem extensão conhecida, o tipo vem do resultado (como veio do original)
+    t := inputType(path, []byte(out), forceType)
+    newName := dest + " (novo)"
+    d := diff.Unified(oldName, newName, prettyForDiff(string(old), t, opts), prettyForDiff(out, t, opts), diffContextLines)
+    if d == "" {
+        // a diferença desaparece na formatação (que também minifica, ex: espaços,
+        // comentários ou o original ao lado do minificado): diff do texto tal
+        // como está
+        d = diff.Unified(oldName, newName, string(old), out, diffContextLines)
+    }
+    return d, nil
+}
+
+// prettyForDiff formata s para o diff; se não der, fica como está. De opts só
+// vem a indentação: com as restantes, a formatação aplicava a mesma alteração aos
+// dois lados e o efeito da opção não aparecia no diff.
+func prettyForDiff(s string, t minifier.Type, opts *minifier.Options) string {
+    if t == minifier.ERROR {
+        return s
+    }
+    format := minifier.DefaultOptions()
+    format.FormatIndentWidth = opts.FormatIndentWidth
+    format.FormatUseTabs = opts.FormatUseTabs
+    pretty, err := minifier.Beautify(s, t, format)
+    if err != nil {
+        return s
+    }
+    if pretty != "" && !strings.HasSuffix(pretty, "\n") {
+        pretty += "\n"
+    }
+    return pretty
+}
@@ -0,0 +1,71 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o modo -diff
+// License: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// o efeito de uma opção aparece no diff, mesmo que a formatação com essa opção
+// tornasse os dois lados iguais
+func TestUnifiedDiffShowsOptionEffect(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "g.json")
+    dest := filepath.Join(dir, "g.min.json")
+    os.WriteFile(path, []byte(`{ "a": "caf\u00e9" }`), 0644)
+    os.WriteFile(dest, []byte(`{"a":"caf\u00e9"}`), 0644)
+
+    opts := minifier.DefaultOptions()
+    opts.JSONNormalizeEscapes = true
+    out, _ := minifier.Minify(`{ "a": "caf\u00e9" }`, minifier.JSON, opts)
+    if out != `{"a":"café"}` {
+        t.Fatalf("minificação: %q", out)
+    }
+
+    d, err := unifiedDiff(path, dest, out, "", opts)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(d, `-  "a": "caf\u00e9"`) || !strings.Contains(d, `+  "a": "café"`) {
+        t.Errorf("diff:\n%s", d)
+    }
+}
+
+// sem ficheiro gerado, compara com o original: a formatação dos dois lados é
+// igual, mas o diff não pode dizer "sem diferenças"
+func TestUnifiedDiffAgainstOriginal(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "a.css")
+    original := "body {\n  color: red;\n}\n"
+    os.WriteFile(path, []byte(original), 0644)
+
+    d, err := unifiedDiff(path, filepath.Join(dir, "a.min.css"), "body{color:red;}", "", minifier.DefaultOptions())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.HasPrefix(d, "--- "+path+" (original)\n") || !strings.Contains(d, "+body{color:red;}") {
+        t.Errorf("diff:\n%s", d)
+    }
+}
+
+func TestUnifiedDiffEqual(t *testing.T) {
+    dir := t.TempDir()
+    dest := filepath.Join(dir, "a.min.js")
+    os.WriteFile(dest, []byte("var a=1;"), 0644)
+
+    d, err := unifiedDiff(filepath.Join(dir, "a.js"), dest, "var a=1;", "", minifier.DefaultOptions())
+    if d != "" || err != nil {
+        t.Errorf("got %q, %v", d, err)
+    }
+    if _, err := unifiedDiff(filepath.Join(dir, "b.js"), filepath.Join(dir, "b.min.js"), "x", "", minifier.DefaultOptions()); err == nil {
+        t.Error("esperado erro sem ficheiro gerado nem original")
+    }
+}
@@ -0,0 +1,72 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: códigos de saída da CLI e resumo dos erros no fim de uma execução.
+//
+//          0  tudo processado sem erros
+//          1  algum ficheiro falhou (leitura, minificação, escrita, -check)
+//          2  erro de utilização (opções ou argumentos inválidos)
+//          3  algum ficheiro falhou a validação (ex: XML mal formado com -validate-xml)
+//
+//          Se houver falhas de validação e de outro tipo, o código é 3.
+// License: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+const (
+    exitOK         = 0
+    exitFailed     = 1
+    exitUsage      = 2
+    exitValidation = 3
+)
+
+// errStale é a falha de um ficheiro gerado desatualizado ou em falta (-check).
+var errStale = errors.New("ficheiro gerado desatualizado")
+
+// failure é um ficheiro que falhou.
+type failure struct {
+    path string
+    err  error
+}
+
+// exitCodeFor devolve o código de saída de um erro.
+func exitCodeFor(err error) int {
+    var se *minifier.XMLSyntaxError
+    if errors.As(err, &se) {
+        return exitValidation
+    }
+    return exitFailed
+}
+
+// exitCode devolve o código de saída para as falhas de uma execução.
+func exitCode(failures []failure, skipped int) int {
+    code := exitOK
+    if skipped > 0 {
+        code = exitFailed
+    }
+    for _, f := range failures {
+        code = max(code, exitCodeFor(f.err))
+    }
+    return code
+}
+
+// printSummary escreve o resumo dos erros (nada se não houve erros).
+func printSummary(w io.Writer, failures []failure, total, skipped int) {
+    if len(failures) == 0 && skipped == 0 {
+        return
+    }
+    fmt.Fprintf(w, "\n%d de %d ficheiro(s) com erro:\n", len(failures), total)
+    for _, f := range failures {
+        fmt.Fprintf(w, "  %s: %v\n", f.path, f.err)
+    }
+    if skipped > 0 {
+        fmt.Fprintf(w, "Interrompido (-fail-fast): %d ficheiro(s) não processado(s)\n", skipped)
+    }
+}
@@ -0,0 +1,57 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para os códigos de saída e o resumo dos erros
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+func TestExitCode(t *testing.T) {
+    xmlErr := minifier.ValidateXML("<a><b></a>")
+    if xmlErr == nil {
+        t.Fatal("esperado erro de validação")
+    }
+    wrapped := fmt.Errorf("feed.xml: %w", xmlErr)
+    other := errors.New("permissão negada")
+
+    tests := []struct {
+        name     string
+        failures []failure
+        skipped  int
+        expected int
+    }{
+        {"sem erros", nil, 0, exitOK},
+        {"erro", []failure{{"a.js", other}}, 0, exitFailed},
+        {"desatualizado", []failure{{"a.min.js", errStale}}, 0, exitFailed},
+        {"ignorados", nil, 2, exitFailed},
+        {"validação", []failure{{"feed.xml", wrapped}}, 0, exitValidation},
+        {"validação e outro", []failure{{"a.js", other}, {"feed.xml", xmlErr}}, 1, exitValidation},
+    }
+    for _, tt := range tests {
+        if got := exitCode(tt.failures, tt.skipped); got != tt.expected {
+            t.Errorf("%s: got %d, want %d", tt.name, got, tt.expected)
+        }
+    }
+}
+
+func TestPrintSummary(t *testing.T) {
+    var b bytes.Buffer
+    printSummary(&b, nil, 3, 0)
+    if b.Len() != 0 {
+        t.Errorf("sem erros: %q", b.String())
+    }
+
+    printSummary(&b, []failure{{"a.js", errors.New("falhou")}}, 5, 2)
+    want := "\n1 de 5 ficheiro(s) com erro:\n  a.js: falhou\nInterrompido (-fail-fast): 2 ficheiro(s) não processado(s)\n"
+    if b.String() != want {
+        t.Errorf("got %q, want %q", b.String(), want)
+    }
+}
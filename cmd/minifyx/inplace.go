@@ -0,0 +1,89 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: escrita atómica para -in-place: o resultado vai para um ficheiro
+//          temporário na mesma pasta, que depois substitui o original com
+//          os.Rename. Quem lê o ficheiro (ex: um servidor em produção) vê sempre
+//          o conteúdo antigo ou o novo, nunca um ficheiro a meio. As permissões
+//          do original são mantidas e, com -backup, o original é guardado antes.
+// License: MIT
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// inPlaceUnsupported devolve os ficheiros de paths que -in-place recusa: só
+// ficheiros de tipos conhecidos, para nunca substituir, por engano, uma imagem ou
+// um binário (aqui conta a extensão ou -type, não o conteúdo).
+func inPlaceUnsupported(paths []string, forceType string) []string {
+    var unsupported []string
+    for _, p := range paths {
+        if forceType == "" && minifier.DetectType(p) == minifier.ERROR {
+            unsupported = append(unsupported, p)
+        }
+    }
+    return unsupported
+}
+
+// writeFileAtomic substitui path por data. Com backupSuffix != "", o conteúdo
+// anterior fica em path+backupSuffix.
+func writeFileAtomic(path string, data []byte, backupSuffix string) error {
+    // com links simbólicos, substituir o ficheiro para onde apontam (e não o link)
+    if real, err := filepath.EvalSymlinks(path); err == nil {
+        path = real
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        return err
+    }
+    mode := info.Mode().Perm()
+
+    if backupSuffix != "" {
+        old, err := os.ReadFile(path)
+        if err != nil {
+            return err
+        }
+        if err := os.WriteFile(path+backupSuffix, old, mode); err != nil {
+            return err
+        }
+    }
+
+    return writeAtomic(path, mode, func(w io.Writer) error {
+        _, err := w.Write(data)
+        return err
+    })
+}
+
+// writeAtomic escreve em path (com permissões mode) o que write produzir. O
+// conteúdo vai para um temporário na mesma pasta, que só substitui path se write
+// e a escrita correrem bem: em caso de erro, path fica como estava.
+func writeAtomic(path string, mode os.FileMode, write func(w io.Writer) error) error {
+    tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+    if err != nil {
+        return err
+    }
+    // em caso de erro o temporário é apagado (depois do Rename já não existe)
+    defer os.Remove(tmp.Name())
+
+    if err := write(tmp); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Chmod(mode); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Sync(); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,151 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a escrita atómica de -in-place e -backup
+// License: MIT
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readFile lê path ou falha o teste.
+func readFile(t *testing.T, path string) string {
+    t.Helper()
+    b, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return string(b)
+}
+
+// assertNoTemp falha se ficou algum temporário de writeAtomic em dir.
+func assertNoTemp(t *testing.T, dir string) {
+    t.Helper()
+    tmps, _ := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+    if len(tmps) > 0 {
+        t.Errorf("temporários não apagados: %q", tmps)
+    }
+}
+
+func TestWriteFileAtomicKeepsMode(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "app.js")
+    os.WriteFile(path, []byte("var a = 1;\n"), 0640)
+    os.Chmod(path, 0640) // sem a umask
+
+    if err := writeFileAtomic(path, []byte("var a=1;"), ""); err != nil {
+        t.Fatal(err)
+    }
+    if got := readFile(t, path); got != "var a=1;" {
+        t.Errorf("conteúdo: %q", got)
+    }
+    info, _ := os.Stat(path)
+    if info.Mode().Perm() != 0640 {
+        t.Errorf("permissões: %v", info.Mode().Perm())
+    }
+    if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+        t.Error("backup criado sem -backup")
+    }
+    assertNoTemp(t, dir)
+}
+
+func TestWriteFileAtomicBackup(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "a.css")
+    os.WriteFile(path, []byte("a { color: red }"), 0600)
+    os.Chmod(path, 0600)
+
+    if err := writeFileAtomic(path, []byte("a{color:red}"), ".bak"); err != nil {
+        t.Fatal(err)
+    }
+    if got := readFile(t, path+".bak"); got != "a { color: red }" {
+        t.Errorf("backup: %q", got)
+    }
+    if got := readFile(t, path); got != "a{color:red}" {
+        t.Errorf("conteúdo: %q", got)
+    }
+    if info, _ := os.Stat(path + ".bak"); info.Mode().Perm() != 0600 {
+        t.Errorf("permissões do backup: %v", info.Mode().Perm())
+    }
+}
+
+// com um link simbólico, é o ficheiro para onde aponta que muda; o link fica
+func TestWriteFileAtomicSymlink(t *testing.T) {
+    dir := t.TempDir()
+    target := filepath.Join(dir, "real", "app.js")
+    os.MkdirAll(filepath.Dir(target), 0755)
+    os.WriteFile(target, []byte("var a = 1;"), 0644)
+    link := filepath.Join(dir, "app.js")
+    if err := os.Symlink(target, link); err != nil {
+        t.Skip("sem links simbólicos:", err)
+    }
+
+    if err := writeFileAtomic(link, []byte("var a=1;"), ""); err != nil {
+        t.Fatal(err)
+    }
+    if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+        t.Errorf("o link foi substituído (%v)", err)
+    }
+    if got := readFile(t, target); got != "var a=1;" {
+        t.Errorf("destino: %q", got)
+    }
+    assertNoTemp(t, dir)
+    assertNoTemp(t, filepath.Dir(target))
+}
+
+// se a escrita falhar, o original fica intacto e o temporário é apagado
+func TestWriteAtomicWriteError(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "o.xml")
+    os.WriteFile(path, []byte("<antigo/>"), 0644)
+
+    want := errors.New("XML inválido")
+    err := writeAtomic(path, 0644, func(w io.Writer) error {
+        io.WriteString(w, "<a>")
+        return want
+    })
+    if !errors.Is(err, want) {
+        t.Errorf("erro: %v", err)
+    }
+    if got := readFile(t, path); got != "<antigo/>" {
+        t.Errorf("original alterado: %q", got)
+    }
+    assertNoTemp(t, dir)
+}
+
+// se o rename falhar (aqui: o destino é uma pasta com conteúdo), o temporário é apagado
+func TestWriteAtomicRenameError(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "pasta")
+    os.MkdirAll(filepath.Join(path, "dentro"), 0755)
+
+    if err := writeFileAtomic(path, []byte("x"), ""); err == nil {
+        t.Error("esperado erro no rename")
+    }
+    assertNoTemp(t, dir)
+}
+
+func TestWriteFileAtomicMissing(t *testing.T) {
+    dir := t.TempDir()
+    if err := writeFileAtomic(filepath.Join(dir, "nada.js"), []byte("x"), ""); !os.IsNotExist(err) {
+        t.Errorf("erro: %v", err)
+    }
+    assertNoTemp(t, dir)
+}
+
+func TestInPlaceUnsupported(t *testing.T) {
+    paths := []string{"a.js", "logo.png", "b.CSS", "LICENSE", "feed.rss"}
+    if got := inPlaceUnsupported(paths, ""); !reflect.DeepEqual(got, []string{"logo.png", "LICENSE"}) {
+        t.Errorf("got %q", got)
+    }
+    // com -type, o utilizador diz o tipo de todos
+    if got := inPlaceUnsupported(paths, "js"); got != nil {
+        t.Errorf("com -type: got %q", got)
+    }
+}
@@ -7,13 +7,19 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pinjoa/minifyx/minifier"
 )
@@ -23,10 +29,16 @@ func main() {
         showVersion bool
         outPath     string
         useStdout   bool
+        stdoutFmt   string
         useStdin    bool
         forceType   string
         parallel    int
 
+        // formatação (modo inverso)
+        pretty     bool
+        indentSize int
+        useTabs    bool
+
         // opções HTML
         preservePreCode    bool // controla tratamento especial de <pre>/<code>
         removeHTMLComments bool // remover comentários <!-- ... -->
@@ -38,15 +50,53 @@ func main() {
         // opções XML (novas)
         removeXMLComments bool
         noXMLWhitespace   bool
+        validateXML       bool
+        xmlPreserveElems  string
+        xmlCleanNS        bool
+        xmlShortPrefixes  bool
+        xmlSelfClose      bool
+        xmlTrimTags       bool
+        xmlNormQuotes     bool
+        minifyEntities    bool
+
+        // opções JSON
+        jsonNormalizeEscapes bool
+        jsonASCII            bool
+
+        // pré-compressão dos ficheiros gerados
+        gzipOut         bool
+        deflateOut      bool
+        compressLevel   int
+        compressMinSize int
+
+        reportFormat string
+        check        bool
+        dryRun       bool
+        showDiff     bool
+        failFast     bool
+        inPlace      bool
+        backupSuffix string
+
+        // opções SVG
+        svgPrecision       int
+        svgRemoveTitleDesc bool
+        svgRemoveIDs       bool
+        noInlineSVG        bool
+        noSVGPaths         bool
     )
 
     flag.BoolVar(&showVersion, "version", false, "mostrar versão")
     flag.StringVar(&outPath, "o", "", "Saída (ficheiro ou diretoria)")
     flag.BoolVar(&useStdout, "stdout", false, "Escrever para stdout")
+    flag.StringVar(&stdoutFmt, "stdout-format", "plain", "Com -stdout e vários ficheiros: plain|headers (\"==> ficheiro <==\")|json (uma linha {path, output, error} por ficheiro)")
     flag.BoolVar(&useStdin, "stdin", false, "Ler de stdin")
-    flag.StringVar(&forceType, "type", "", "Forçar tipo: html|css|js|json|xml")
+    flag.StringVar(&forceType, "type", "", "Forçar tipo: html|css|js|json|xml|svg (por omissão: pela extensão ou, se não for conhecida, pelo conteúdo)")
     flag.IntVar(&parallel, "parallel", runtime.NumCPU(), "Número de goroutines em paralelo")
 
+    flag.BoolVar(&pretty, "pretty", false, "Formatar (pretty-print) em vez de minificar")
+    flag.IntVar(&indentSize, "indent", 2, "Espaços por nível de indentação (com -pretty)")
+    flag.BoolVar(&useTabs, "tabs", false, "Indentar com tabs (com -pretty)")
+
     flag.BoolVar(&preservePreCode, "preserve-precode", true, "Preservar conteúdo especial em <pre>/<code> (e tratar <code> como bloco)")
     flag.BoolVar(&removeHTMLComments, "remove-html-comments", true, "Remover comentários HTML")
 
@@ -56,6 +106,40 @@ func main() {
 
     flag.BoolVar(&removeXMLComments, "remove-xml-comments", true, "Remover comentários XML")
     flag.BoolVar(&noXMLWhitespace, "no-xml-whitespace", false, "Não colapsar espaços/indentação em XML")
+    flag.StringVar(&xmlPreserveElems, "xml-preserve-elements", "", "Elementos XML cujo texto não é mexido, separados por vírgula (substitui a lista por omissão)")
+    flag.BoolVar(&xmlCleanNS, "xml-clean-ns", false, "Remover declarações de namespaces XML redundantes ou não usadas")
+    flag.BoolVar(&xmlShortPrefixes, "xml-short-prefixes", false, "Encurtar prefixos de namespaces XML (implica -xml-clean-ns)")
+    flag.BoolVar(&xmlSelfClose, "xml-self-close", false, "Escrever elementos XML vazios como <item/>")
+    flag.BoolVar(&xmlTrimTags, "xml-trim-tags", false, "Remover espaços antes de /> e à volta de = nas tags XML")
+    flag.BoolVar(&xmlNormQuotes, "xml-normalize-quotes", false, "Usar nas aspas dos atributos XML as que precisam de menos escapes")
+    flag.BoolVar(&minifyEntities, "entities", false, "Minimizar referências a caracteres/entidades em HTML e XML (&eacute; → é)")
+    flag.BoolVar(&validateXML, "validate-xml", false, "Verificar se o XML é bem formado (erro com linha/coluna)")
+
+    flag.IntVar(&svgPrecision, "svg-precision", 3, "Casas decimais nos números SVG (-1 = não arredondar)")
+    flag.BoolVar(&svgRemoveTitleDesc, "svg-remove-title-desc", false, "Remover <title> e <desc> em SVG")
+    flag.BoolVar(&svgRemoveIDs, "svg-remove-ids", false, "Remover ids SVG não referenciados no próprio SVG")
+    flag.BoolVar(&noSVGPaths, "no-svg-paths", false, "Não compactar d/points/viewBox/transform em SVG")
+    flag.BoolVar(&noInlineSVG, "no-html-svg", false, "Não otimizar <svg> embebido em HTML")
+
+    flag.BoolVar(&jsonNormalizeEscapes, "json-normalize-escapes", false, "Reescrever escapes de strings JSON na forma mais curta")
+    flag.BoolVar(&jsonASCII, "json-ascii", false, "Escapar todo o não-ASCII em strings JSON (\\uXXXX)")
+
+    flag.BoolVar(&gzipOut, "gzip", false, "Escrever também uma versão .gz de cada ficheiro gerado (gzip_static do nginx)")
+    flag.BoolVar(&deflateOut, "deflate", false, "Escrever também uma versão .deflate (zlib) de cada ficheiro gerado")
+    flag.IntVar(&compressLevel, "compress-level", 9, "Nível de compressão de -gzip/-deflate (1 = rápido … 9 = melhor)")
+    flag.IntVar(&compressMinSize, "compress-min-size", 256, "Não comprimir ficheiros com menos bytes do que isto")
+
+    flag.StringVar(&reportFormat, "report", "", "Relatório de tamanhos no fim: table|json (ordenado pelo caminho)")
+
+    flag.BoolVar(&check, "check", false, "Não escrever nada: verificar se os ficheiros gerados existentes estão atualizados (erro se não)")
+
+    flag.BoolVar(&dryRun, "dry-run", false, "Não escrever nada: listar os ficheiros que seriam escritos")
+    flag.BoolVar(&showDiff, "diff", false, "Não escrever nada: mostrar o diff unificado entre o ficheiro gerado atual (ou o original) e o novo")
+
+    flag.BoolVar(&failFast, "fail-fast", false, "Parar no primeiro erro (os ficheiros ainda não processados são cancelados)")
+
+    flag.BoolVar(&inPlace, "in-place", false, "Substituir cada ficheiro pelo resultado (escrita atómica, mantém as permissões)")
+    flag.StringVar(&backupSuffix, "backup", "", "Com -in-place: guardar o original com este sufixo (ex: .bak)")
 
     flag.Parse()
 
@@ -64,13 +148,89 @@ func main() {
         return
     }
 
+    if reportFormat != "" && reportFormat != "table" && reportFormat != "json" {
+        fmt.Fprintln(os.Stderr, "-report tem de ser table ou json")
+        os.Exit(exitUsage)
+    }
+    if stdoutFmt != "plain" && stdoutFmt != "headers" && stdoutFmt != "json" {
+        fmt.Fprintln(os.Stderr, "-stdout-format tem de ser plain, headers ou json")
+        os.Exit(exitUsage)
+    }
+    if (check || dryRun || showDiff) && (useStdin || useStdout) {
+        fmt.Fprintln(os.Stderr, "-check, -dry-run e -diff não podem ser usados com -stdin ou -stdout")
+        os.Exit(exitUsage)
+    }
+    if inPlace && (useStdin || useStdout || outPath != "") {
+        fmt.Fprintln(os.Stderr, "-in-place não pode ser usado com -stdin, -stdout ou -o")
+        os.Exit(exitUsage)
+    }
+    if backupSuffix != "" && !inPlace {
+        fmt.Fprintln(os.Stderr, "-backup só pode ser usado com -in-place")
+        os.Exit(exitUsage)
+    }
+    if check && (dryRun || showDiff) {
+        fmt.Fprintln(os.Stderr, "-check não pode ser usado com -dry-run ou -diff")
+        os.Exit(exitUsage)
+    }
+    if forceType != "" && typeByName(forceType) == minifier.ERROR {
+        fmt.Fprintln(os.Stderr, "-type tem de ser html, css, js, json, xml ou svg")
+        os.Exit(exitUsage)
+    }
+    if compressLevel < 1 || compressLevel > 9 {
+        fmt.Fprintln(os.Stderr, "-compress-level tem de estar entre 1 e 9")
+        os.Exit(exitUsage)
+    }
+    compress := compressConfig{
+        gzip:    gzipOut,
+        deflate: deflateOut,
+        level:   compressLevel,
+        minSize: compressMinSize,
+        dryRun:  dryRun || showDiff,
+    }
+
     opts := minifier.DefaultOptions()
     opts.XMLRemoveComments = removeXMLComments
+    opts.XMLValidate = validateXML
+    opts.XMLCleanNamespaces = xmlCleanNS
+    opts.XMLShortenPrefixes = xmlShortPrefixes
+    opts.XMLSelfCloseEmpty = xmlSelfClose
+    opts.XMLTrimTagWhitespace = xmlTrimTags
+    opts.XMLNormalizeQuotes = xmlNormQuotes
+    opts.XMLMinifyEntities = minifyEntities
+    opts.MinifyHTMLEntities = minifyEntities
+    if xmlPreserveElems != "" {
+        opts.XMLPreserveWhitespaceElements = nil
+        for _, name := range strings.Split(xmlPreserveElems, ",") {
+            if name = strings.TrimSpace(name); name != "" {
+                opts.XMLPreserveWhitespaceElements = append(opts.XMLPreserveWhitespaceElements, name)
+            }
+        }
+    }
     if noXMLWhitespace {
         opts.XMLCollapseTagWhitespace =  false
         opts.XMLCollapseAttrWhitespace = false
+        opts.XMLTrimTagWhitespace =      false
     }
 
+    // Formatação
+    opts.FormatIndentWidth = indentSize
+    opts.FormatUseTabs =     useTabs
+
+    // SVG
+    opts.SVGPrecision =       svgPrecision
+    opts.SVGRemoveTitleDesc = svgRemoveTitleDesc
+    opts.SVGRemoveUnusedIDs = svgRemoveIDs
+    if noInlineSVG {
+        opts.MinifyInlineSVG = false
+    }
+    if noSVGPaths {
+        opts.SVGCompactPaths = false
+    }
+
+    // JSON
+    opts.JSONNormalizeEscapes = jsonNormalizeEscapes
+    opts.JSONASCIIOnly =        jsonASCII
+
     // Comentários HTML
     opts.RemoveHTMLComments = removeHTMLComments
 
@@ -108,112 +268,405 @@ func main() {
     }
 
     if useStdin {
-        reader := bufio.NewReader(os.Stdin)
-        var b strings.Builder
-        for {
-            chunk, err := reader.ReadString('\n')
-            b.WriteString(chunk)
-            if err == io.EOF {
-                break
+        // sem -type, o tipo é detetado pelo início do conteúdo (o Peek não o
+        // consome: o reader continua a ter tudo)
+        in := bufio.NewReader(os.Stdin)
+        t := typeByName(forceType)
+        if forceType == "" {
+            head, _ := in.Peek(1024)
+            t = minifier.SniffType(head)
+            if t == minifier.ERROR {
+                fmt.Fprintln(os.Stderr, "Não foi possível detetar o tipo do stdin: use -type (html|css|js|json|xml|svg)")
+                os.Exit(exitUsage)
             }
+        }
+
+        write := func(w io.Writer) error {
+            if !pretty {
+                // minificação em streaming: JSON, XML e CSS não ficam em memória
+                return minifier.MinifyStream(w, in, t, opts)
+            }
+            input, err := io.ReadAll(in)
             if err != nil {
-                fmt.Fprintln(os.Stderr, "Erro ao ler stdin:", err)
-                os.Exit(1)
-            }
-        }
-        input := b.String()
-        var t minifier.Type
-        switch strings.ToLower(forceType) {
-        case "html":
-            t = minifier.HTML
-        case "css":
-            t = minifier.CSS
-        case "js":
-            t = minifier.JS
-        case "json":
-            t = minifier.JSON
-        case "xml":
-            t = minifier.XML
-        default:
-            fmt.Fprintln(os.Stderr, "É necessário -type quando usa -stdin (html|css|js|json|xml)")
-            os.Exit(2)
-        }
-        out, err := minifier.Minify(input, t, opts)
-        if err != nil {
-            fmt.Fprintln(os.Stderr, err)
-            os.Exit(2)
+                return err
+            }
+            out, err := minifier.Beautify(string(input), t, opts)
+            if err != nil {
+                return err
+            }
+            _, err = io.WriteString(w, out)
+            return err
         }
+
+        var err error
         if useStdout || outPath == "" {
-            fmt.Print(out)
+            err = write(os.Stdout)
         } else {
-            if err := os.WriteFile(outPath, []byte(out), 0644); err != nil {
-                fmt.Fprintln(os.Stderr, "Erro a escrever saída:", err)
-                os.Exit(1)
+            // -o só é substituído no fim, se tudo correr bem: um erro (ex: XML
+            // mal formado) não deixa o ficheiro anterior truncado
+            mode := os.FileMode(0644)
+            if info, statErr := os.Stat(outPath); statErr == nil {
+                mode = info.Mode().Perm()
             }
+            err = writeAtomic(outPath, mode, write)
+        }
+        if err != nil {
+            fmt.Fprintln(os.Stderr, "Erro:", err)
+            os.Exit(exitCodeFor(err))
         }
         return
     }
 
     args := flag.Args()
     if len(args) == 0 {
-        fmt.Println("Uso: minifyx [opções] <ficheiros...>\n\nou: minifyx -help\n\nEx.: minifyx -parallel 4 index.html style.css app.js")
-        os.Exit(0)
+        fmt.Println("Uso: minifyx [opções] <ficheiros...>\n     minifyx [opções] build [-o dist] <pasta>\n     minifyx [opções] serve [-addr 127.0.0.1:8080]\n\nou: minifyx -help\n\nEx.: minifyx -parallel 4 index.html style.css app.js")
+        os.Exit(exitUsage)
+    }
+
+    // minifyx build: árvore de assets → pasta com nomes com hash + manifest.json
+    if args[0] == "build" {
+        os.Exit(runBuild(args[1:], opts, parallel, compress))
+    }
+
+    // minifyx serve: serviço HTTP local (POST /minify, /api/minify, /health)
+    if args[0] == "serve" {
+        os.Exit(runServe(args[1:], opts))
+    }
+
+    if inPlace {
+        if unsupported := inPlaceUnsupported(args, forceType); len(unsupported) > 0 {
+            fmt.Fprintln(os.Stderr, "-in-place: tipo não suportado (nada foi alterado):", strings.Join(unsupported, ", "))
+            os.Exit(exitUsage)
+        }
+    }
+
+    // com -fail-fast, o primeiro erro cancela os ficheiros que ainda não começaram
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    start := time.Now()
+    results := runWorkers(ctx, args, parallel, func(path string) (string, error) {
+        b, err := os.ReadFile(path)
+        if err != nil {
+            return "", err
+        }
+        t := inputType(path, b, forceType)
+        if t == minifier.ERROR {
+            return "", errors.New("tipo não suportado (use -type)")
+        }
+        if pretty {
+            return minifier.Beautify(string(b), t, opts)
+        }
+        return minifier.Minify(string(b), t, opts)
+    })
+    if useStdout || dryRun || showDiff {
+        // o que vai para o ecrã sai pela ordem dos argumentos (a minificação
+        // continua em paralelo)
+        results = inOrder(results)
+    }
+
+    // sufixo dos ficheiros gerados: app.js → app.min.js; com -pretty,
+    // app.min.js → app.pretty.js
+    suffix := ".min"
+    if pretty {
+        suffix = ".pretty"
+    }
+
+    // o relatório vai para stdout, exceto se o stdout já tiver o conteúdo
+    var reportOut io.Writer = os.Stdout
+    if useStdout {
+        reportOut = os.Stderr
+    }
+    var entries []reportEntry
+    var failures []failure
+    processed := 0
+    fail := func(path string, err error) {
+        failures = append(failures, failure{path: path, err: err})
+        if failFast {
+            cancel()
+        }
+    }
+
+    for r := range results {
+        processed++
+        if useStdout {
+            writeStdout(os.Stdout, stdoutFmt, r)
+        }
+        if r.err != nil {
+            if !useStdout || stdoutFmt != "json" {
+                fmt.Fprintln(os.Stderr, "Erro:", r.path, r.err)
+            }
+            entries = append(entries, reportEntry{Path: r.path, Error: r.err.Error()})
+            fail(r.path, r.err)
+            continue
+        }
+        entry := reportEntry{
+            Path:     r.path,
+            Minified: int64(len(r.out)),
+            Elapsed:  milliseconds(r.elapsed),
+        }
+        if reportFormat != "" {
+            if info, err := os.Stat(r.path); err == nil {
+                entry.Original = info.Size()
+            }
+            entry.Gzip = int64(gzipSize([]byte(r.out), compressLevel))
+            entry.Saved = savedPercent(entry.Original, entry.Minified)
+        }
+        if useStdout {
+            entries = append(entries, entry)
+            continue
+        }
+        dest := r.path
+        if !inPlace {
+            dest = destPath(r.path, outPath, suffix, pretty, forceType)
+        }
+        if check {
+            ok, summary, err := checkOutput(dest, r.out)
+            if err != nil {
+                fmt.Fprintln(os.Stderr, "Erro:", dest, err)
+                entries = append(entries, reportEntry{Path: r.path, Output: dest, Error: err.Error()})
+                fail(dest, err)
+                continue
+            }
+            entry.Output = dest
+            entries = append(entries, entry)
+            if !ok {
+                fmt.Fprintln(os.Stderr, summary)
+                fail(dest, errStale)
+            }
+            continue
+        }
+        if dryRun || showDiff {
+            entry.Output = dest
+            if showDiff {
+                printDiff(r.path, dest, r.out, forceType, opts)
+            }
+            if dryRun {
+                compressed, _ := writeCompressed(dest, []byte(r.out), compress)
+                entry.Deflate = int64(compressedSize(compressed, ".deflate"))
+                fmt.Printf("Escreveria: %s (%d B)%s\n", dest, len(r.out), formatCompressed(compressed))
+            }
+            entries = append(entries, entry)
+            continue
+        }
+        var err error
+        if inPlace {
+            err = writeFileAtomic(dest, []byte(r.out), backupSuffix)
+        } else {
+            err = os.WriteFile(dest, []byte(r.out), 0644)
+        }
+        if err != nil {
+            fmt.Fprintln(os.Stderr, "Erro a escrever:", dest, err)
+            entries = append(entries, reportEntry{Path: r.path, Output: dest, Error: err.Error()})
+            fail(dest, err)
+            continue
+        }
+        entry.Output = dest
+        compressed, err := writeCompressed(dest, []byte(r.out), compress)
+        if err != nil {
+            fmt.Fprintln(os.Stderr, "Erro a comprimir:", dest, err)
+            fail(dest, err)
+        }
+        entry.Deflate = int64(compressedSize(compressed, ".deflate"))
+        entries = append(entries, entry)
+        if reportFormat != "" {
+            continue // os tamanhos aparecem no relatório
+        }
+        if pretty {
+            fmt.Println("Formatado:", dest)
+        } else {
+            fmt.Printf("Minificado: %s (%d B)%s\n", dest, len(r.out), formatCompressed(compressed))
+        }
+    }
+
+    if reportFormat != "" {
+        if err := writeReport(reportOut, reportFormat, newReport(entries, time.Since(start))); err != nil {
+            fmt.Fprintln(os.Stderr, "Erro a escrever relatório:", err)
+        }
     }
 
-    type job struct { path string }
-    type result struct { path string; out string; err error }
+    skipped := len(args) - processed
+    printSummary(os.Stderr, failures, len(args), skipped)
+    code := exitCode(failures, skipped)
+    if check && code == exitOK && reportFormat == "" {
+        fmt.Printf("%d ficheiro(s) atualizado(s)\n", len(entries))
+    }
+    os.Exit(code)
+}
 
-    jobs := make(chan job)
+// typeByName devolve o tipo de um valor de -type (ERROR se não for conhecido).
+func typeByName(name string) minifier.Type {
+    switch strings.ToLower(name) {
+    case "html":
+        return minifier.HTML
+    case "css":
+        return minifier.CSS
+    case "js":
+        return minifier.JS
+    case "json":
+        return minifier.JSON
+    case "xml":
+        return minifier.XML
+    case "svg":
+        return minifier.SVG
+    }
+    return minifier.ERROR
+}
+
+// typeName é o inverso de typeByName.
+func typeName(t minifier.Type) string {
+    for _, name := range []string{"html", "css", "js", "json", "xml", "svg"} {
+        if typeByName(name) == t {
+            return name
+        }
+    }
+    return ""
+}
+
+// inputType devolve o tipo de um ficheiro: o de -type, o da extensão ou, se a
+// extensão não for conhecida, o detetado pelo conteúdo.
+func inputType(path string, content []byte, forceType string) minifier.Type {
+    if forceType != "" {
+        return typeByName(forceType)
+    }
+    if t := minifier.DetectType(path); t != minifier.ERROR {
+        return t
+    }
+    return minifier.SniffType(content)
+}
+
+// destPath devolve o ficheiro gerado para path: app.js → app.min.js (ou
+// app.pretty.js com -pretty), na mesma pasta ou na pasta de -o.
+func destPath(path, outPath, suffix string, pretty bool, forceType string) string {
+    dest := outPath
+    if dest == "" {
+        ext := filepath.Ext(path)
+        base := strings.TrimSuffix(path, ext)
+        if pretty {
+            base = strings.TrimSuffix(base, ".min")
+        }
+        return base + suffix + ext
+    }
+    info, _ := os.Stat(dest)
+    if info != nil && info.IsDir() {
+        ext := filepath.Ext(path)
+        name := filepath.Base(strings.TrimSuffix(path, ext))
+        if pretty {
+            name = strings.TrimSuffix(name, ".min")
+        }
+        if forceType != "" {
+            ext = "." + forceType
+        }
+        dest = filepath.Join(dest, name+suffix+ext)
+    }
+    return dest
+}
+
+type result struct { index int; path string; out string; err error; elapsed time.Duration }
+
+// runWorkers processa paths com parallel goroutines; os resultados chegam pela
+// ordem em que terminam e o canal fecha no fim.
+//
+// Quando ctx é cancelado, os ficheiros que ainda não começaram são ignorados (os
+// que já estão a ser processados terminam e o resultado chega normalmente).
+func runWorkers(ctx context.Context, paths []string, parallel int, work func(path string) (string, error)) <-chan result {
+    if parallel < 1 {
+        parallel = 1
+    }
+    jobs := make(chan int)
     results := make(chan result)
 
+    var wg sync.WaitGroup
     for i := 0; i < parallel; i++ {
+        wg.Add(1)
         go func() {
-            for j := range jobs {
-                out, err := minifier.MinifyFile(j.path, opts)
-                results <- result{path: j.path, out: out, err: err}
+            defer wg.Done()
+            for i := range jobs {
+                if ctx.Err() != nil {
+                    continue
+                }
+                t := time.Now()
+                out, err := work(paths[i])
+                results <- result{index: i, path: paths[i], out: out, err: err, elapsed: time.Since(t)}
             }
         }()
     }
 
     go func() {
-        for _, p := range args {
-            jobs <- job{path: p}
+    feed:
+        for i := range paths {
+            select {
+            case jobs <- i:
+            case <-ctx.Done():
+                break feed
+            }
         }
         close(jobs)
+        wg.Wait()
+        close(results)
     }()
+    return results
+}
 
-    pending := len(args)
-    for pending > 0 {
-        r := <-results
-        pending--
-        if r.err != nil {
-            fmt.Fprintln(os.Stderr, "Erro:", r.path, r.err)
-            continue
+// inOrder devolve os resultados pela ordem dos argumentos: cada um sai assim que
+// todos os anteriores já saíram. Se faltarem índices (ficheiros ignorados depois
+// de um cancelamento), os resultados que ficaram à espera saem no fim, também
+// por ordem.
+func inOrder(results <-chan result) <-chan result {
+    out := make(chan result)
+    go func() {
+        defer close(out)
+        pending := make(map[int]result)
+        next := 0
+        for r := range results {
+            pending[r.index] = r
+            for {
+                p, ok := pending[next]
+                if !ok {
+                    break
+                }
+                delete(pending, next)
+                out <- p
+                next++
+            }
         }
-        if useStdout {
-            fmt.Println(r.out)
-            continue
+        rest := make([]int, 0, len(pending))
+        for i := range pending {
+            rest = append(rest, i)
         }
-        dest := outPath
-        if dest == "" {
-            ext := filepath.Ext(r.path)
-            base := strings.TrimSuffix(r.path, ext)
-            dest = base + ".min" + ext
-        } else {
-            info, _ := os.Stat(dest)
-            if info != nil && info.IsDir() {
-                ext := filepath.Ext(r.path)
-                name := filepath.Base(strings.TrimSuffix(r.path, ext))
-                if forceType != "" {
-                    ext = "." + forceType
-                }
-                dest = filepath.Join(dest, name+".min"+ext)
+        slices.Sort(rest)
+        for _, i := range rest {
+            out <- pending[i]
+        }
+    }()
+    return out
+}
+
+// writeStdout escreve um resultado no stdout (-stdout) no formato de
+// -stdout-format. Em plain e headers os erros vão só para o stderr.
+func writeStdout(w io.Writer, format string, r result) {
+    switch format {
+    case "json":
+        line := struct {
+            Path   string `json:"path"`
+            Output string `json:"output"`
+            Error  string `json:"error,omitempty"`
+        }{Path: r.path, Output: r.out}
+        if r.err != nil {
+            line.Error = r.err.Error()
+        }
+        b, _ := json.Marshal(line)
+        fmt.Fprintf(w, "%s\n", b)
+    case "headers":
+        if r.err == nil {
+            if r.index > 0 {
+                fmt.Fprintln(w)
             }
+            fmt.Fprintf(w, "==> %s <==\n%s\n", r.path, r.out)
         }
-        if err := os.WriteFile(dest, []byte(r.out), 0644); err != nil {
-            fmt.Fprintln(os.Stderr, "Erro a escrever:", dest, err)
-        } else {
-            fmt.Println("Minificado:", dest)
+    default:
+        if r.err == nil {
+            fmt.Fprintln(w, r.out)
         }
     }
 }
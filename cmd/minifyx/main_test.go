@@ -0,0 +1,133 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para as funções auxiliares da CLI (nomes dos ficheiros
+//          gerados, workers, ordem dos resultados e formatos de -stdout)
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDestPath(t *testing.T) {
+    outDir := t.TempDir()
+    tests := []struct {
+        name      string
+        path      string
+        outPath   string
+        suffix    string
+        pretty    bool
+        forceType string
+        expected  string
+    }{
+        {"minificar", "css/site.css", "", ".min", false, "", "css/site.min.css"},
+        {"sem extensão", "LICENSE", "", ".min", false, "", "LICENSE.min"},
+        {"pretty", "js/app.min.js", "", ".pretty", true, "", "js/app.pretty.js"},
+        {"-o ficheiro", "a.js", "saida.js", ".min", false, "", "saida.js"},
+        {"-o pasta", "src/a.js", outDir, ".min", false, "", filepath.Join(outDir, "a.min.js")},
+        {"-o pasta pretty", "src/a.min.js", outDir, ".pretty", true, "", filepath.Join(outDir, "a.pretty.js")},
+        {"-o pasta com -type", "snippet.txt", outDir, ".min", false, "js", filepath.Join(outDir, "snippet.min.js")},
+    }
+    for _, tt := range tests {
+        got := destPath(tt.path, tt.outPath, tt.suffix, tt.pretty, tt.forceType)
+        if got != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.name, got, tt.expected)
+        }
+    }
+}
+
+func TestRunWorkersInOrder(t *testing.T) {
+    paths := make([]string, 20)
+    for i := range paths {
+        paths[i] = fmt.Sprintf("f%02d", i)
+    }
+    work := func(path string) (string, error) {
+        // os primeiros demoram mais: terminam fora de ordem
+        var i int
+        fmt.Sscanf(path, "f%d", &i)
+        time.Sleep(time.Duration(20-i) * 200 * time.Microsecond)
+        return strings.ToUpper(path), nil
+    }
+
+    i := 0
+    for r := range inOrder(runWorkers(context.Background(), paths, 4, work)) {
+        if r.index != i || r.path != paths[i] || r.out != strings.ToUpper(paths[i]) || r.err != nil {
+            t.Errorf("resultado %d: %+v", i, r)
+        }
+        i++
+    }
+    if i != len(paths) {
+        t.Errorf("%d resultados, esperados %d", i, len(paths))
+    }
+}
+
+// depois do cancelamento, os ficheiros que ainda não começaram são ignorados
+func TestRunWorkersCancel(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    paths := []string{"a", "b", "erro", "c", "d", "e"}
+    work := func(path string) (string, error) {
+        if path == "erro" {
+            cancel()
+            return "", errors.New("falhou")
+        }
+        return path, nil
+    }
+
+    var got []string
+    for r := range runWorkers(ctx, paths, 1, work) {
+        got = append(got, r.path)
+    }
+    if strings.Join(got, " ") != "a b erro" {
+        t.Errorf("got %q", got)
+    }
+}
+
+// com índices em falta (cancelamento), os resultados à espera não se perdem
+func TestInOrderFlushesAfterGap(t *testing.T) {
+    in := make(chan result, 4)
+    for _, i := range []int{3, 0, 4, 2} {
+        in <- result{index: i}
+    }
+    close(in)
+
+    var got []int
+    for r := range inOrder(in) {
+        got = append(got, r.index)
+    }
+    if fmt.Sprint(got) != "[0 2 3 4]" {
+        t.Errorf("got %v", got)
+    }
+}
+
+func TestWriteStdout(t *testing.T) {
+    results := []result{
+        {index: 0, path: "a.css", out: "a{}"},
+        {index: 1, path: "b.xml", err: errors.New("XML inválido")},
+        {index: 2, path: "c.js", out: "c()"},
+    }
+    tests := map[string]string{
+        "plain":   "a{}\nc()\n",
+        "headers": "==> a.css <==\na{}\n\n==> c.js <==\nc()\n",
+        "json": `{"path":"a.css","output":"a{}"}` + "\n" +
+            `{"path":"b.xml","output":"","error":"XML inválido"}` + "\n" +
+            `{"path":"c.js","output":"c()"}` + "\n",
+    }
+    for format, want := range tests {
+        var b bytes.Buffer
+        for _, r := range results {
+            writeStdout(&b, format, r)
+        }
+        if b.String() != want {
+            t.Errorf("%s: got %q, want %q", format, b.String(), want)
+        }
+    }
+}
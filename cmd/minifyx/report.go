@@ -0,0 +1,134 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: relatório de tamanhos e poupança (-report table|json): bytes originais,
+//          minificados, tamanho em gzip (e do .deflate escrito, com -deflate),
+//          percentagem poupada e tempo, por ficheiro e no total. Os ficheiros
+//          aparecem ordenados pelo caminho (e não pela ordem em que os workers
+//          terminam), para o relatório ser comparável entre execuções (ex:
+//          dashboards de CI).
+// License: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+)
+
+// reportEntry é uma linha do relatório.
+type reportEntry struct {
+    Path     string  `json:"path"`
+    Output   string  `json:"output,omitempty"`
+    Original int64   `json:"original"`
+    Minified int64   `json:"minified"`
+    Gzip     int64   `json:"gzip"`
+    Deflate  int64   `json:"deflate,omitempty"` // .deflate escrito (-deflate); 0 se não foi
+    Saved    float64 `json:"saved_percent"`
+    Elapsed  float64 `json:"elapsed_ms"`
+    Error    string  `json:"error,omitempty"`
+}
+
+// reportTotal é o resumo de todos os ficheiros processados sem erro (Elapsed é
+// o tempo total da execução, não a soma dos ficheiros).
+type reportTotal struct {
+    Files    int     `json:"files"`
+    Errors   int     `json:"errors"`
+    Original int64   `json:"original"`
+    Minified int64   `json:"minified"`
+    Gzip     int64   `json:"gzip"`
+    Deflate  int64   `json:"deflate,omitempty"`
+    Saved    float64 `json:"saved_percent"`
+    Elapsed  float64 `json:"elapsed_ms"`
+}
+
+type report struct {
+    Files []reportEntry `json:"files"`
+    Total reportTotal   `json:"total"`
+}
+
+// newReport ordena as entradas e calcula os totais.
+func newReport(entries []reportEntry, elapsed time.Duration) report {
+    sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+    r := report{Files: entries}
+    if r.Files == nil {
+        r.Files = []reportEntry{}
+    }
+    for _, e := range entries {
+        if e.Error != "" {
+            r.Total.Errors++
+            continue
+        }
+        r.Total.Files++
+        r.Total.Original += e.Original
+        r.Total.Minified += e.Minified
+        r.Total.Gzip += e.Gzip
+        r.Total.Deflate += e.Deflate
+    }
+    r.Total.Saved = savedPercent(r.Total.Original, r.Total.Minified)
+    r.Total.Elapsed = milliseconds(elapsed)
+    return r
+}
+
+// writeReport escreve o relatório no formato pedido ("table" ou "json").
+func writeReport(w io.Writer, format string, r report) error {
+    if format == "json" {
+        enc := json.NewEncoder(w)
+        enc.SetIndent("", "  ")
+        return enc.Encode(r)
+    }
+
+    // números alinhados à direita; a coluna dos caminhos fica à esquerda por
+    // ter sempre a mesma largura
+    t := r.Total
+    total := fmt.Sprintf("Total (%d)", t.Files)
+    width := utf8.RuneCountInString(total)
+    for _, e := range r.Files {
+        width = max(width, utf8.RuneCountInString(e.Path))
+    }
+    // a coluna deflate só aparece se houver ficheiros .deflate (-deflate)
+    deflate := func(n int64) string { return "" }
+    if t.Deflate > 0 {
+        deflate = func(n int64) string {
+            if n == 0 {
+                return "-\t"
+            }
+            return fmt.Sprintf("%d B\t", n)
+        }
+    }
+    tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+    header := "Original\tMinificado\tgzip\t"
+    if t.Deflate > 0 {
+        header += "deflate\t"
+    }
+    fmt.Fprintf(tw, "%-*s\t%sPoupança\tTempo\t\n", width, "Ficheiro", header)
+    for _, e := range r.Files {
+        if e.Error != "" {
+            fmt.Fprintf(tw, "%-*s\t%serro\t\n", width, e.Path, strings.Repeat("\t", strings.Count(header, "\t")+1))
+            continue
+        }
+        fmt.Fprintf(tw, "%-*s\t%d B\t%d B\t%d B\t%s%.1f%%\t%.1f ms\t\n",
+            width, e.Path, e.Original, e.Minified, e.Gzip, deflate(e.Deflate), e.Saved, e.Elapsed)
+    }
+    fmt.Fprintf(tw, "%-*s\t%d B\t%d B\t%d B\t%s%.1f%%\t%.1f ms\t\n",
+        width, total, t.Original, t.Minified, t.Gzip, deflate(t.Deflate), t.Saved, t.Elapsed)
+    return tw.Flush()
+}
+
+// savedPercent é a percentagem poupada (negativa se o resultado for maior, ex: -pretty).
+func savedPercent(original, minified int64) float64 {
+    if original == 0 {
+        return 0
+    }
+    return math.Round(float64(original-minified)/float64(original)*10000) / 100
+}
+
+func milliseconds(d time.Duration) float64 {
+    return math.Round(float64(d.Microseconds())) / 1000
+}
@@ -0,0 +1,150 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o relatório de tamanhos (-report table|json)
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReport(t *testing.T) {
+    entries := []reportEntry{
+        {Path: "z.css", Original: 200, Minified: 150, Gzip: 90},
+        {Path: "a.js", Original: 1000, Minified: 600, Gzip: 300},
+        {Path: "m.xml", Error: "XML inválido"},
+    }
+    r := newReport(entries, 1500*time.Microsecond)
+
+    var paths []string
+    for _, e := range r.Files {
+        paths = append(paths, e.Path)
+    }
+    if got := strings.Join(paths, " "); got != "a.js m.xml z.css" {
+        t.Errorf("ordem: %s", got)
+    }
+    want := reportTotal{Files: 2, Errors: 1, Original: 1200, Minified: 750, Gzip: 390, Saved: 37.5, Elapsed: 1.5}
+    if r.Total != want {
+        t.Errorf("total: got %+v, want %+v", r.Total, want)
+    }
+
+    if empty := newReport(nil, 0); empty.Files == nil || empty.Total.Saved != 0 {
+        t.Errorf("sem ficheiros: %+v", empty)
+    }
+}
+
+func TestSavedPercent(t *testing.T) {
+    tests := []struct {
+        original, minified int64
+        expected           float64
+    }{
+        {1000, 600, 40},
+        {3, 2, 33.33},
+        {100, 120, -20},
+        {0, 0, 0},
+    }
+    for _, tt := range tests {
+        if got := savedPercent(tt.original, tt.minified); got != tt.expected {
+            t.Errorf("%d → %d: got %v, want %v", tt.original, tt.minified, got, tt.expected)
+        }
+    }
+}
+
+func TestWriteReportJSON(t *testing.T) {
+    r := newReport([]reportEntry{
+        {Path: "a.js", Output: "a.min.js", Original: 10, Minified: 5, Gzip: 20, Saved: 50, Elapsed: 0.25},
+        {Path: "b.js", Error: "falhou"},
+    }, time.Millisecond)
+    var b bytes.Buffer
+    if err := writeReport(&b, "json", r); err != nil {
+        t.Fatal(err)
+    }
+
+    var got map[string]any
+    if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+        t.Fatalf("JSON inválido: %v\n%s", err, b.String())
+    }
+    files := got["files"].([]any)
+    first := files[0].(map[string]any)
+    if first["path"] != "a.js" || first["output"] != "a.min.js" || first["saved_percent"] != 50.0 || first["elapsed_ms"] != 0.25 {
+        t.Errorf("files[0]: %v", first)
+    }
+    if _, ok := first["error"]; ok {
+        t.Error("error devia ser omitido sem erro")
+    }
+    if second := files[1].(map[string]any); second["error"] != "falhou" {
+        t.Errorf("files[1]: %v", second)
+    }
+    total := got["total"].(map[string]any)
+    if total["files"] != 1.0 || total["errors"] != 1.0 || total["minified"] != 5.0 {
+        t.Errorf("total: %v", total)
+    }
+}
+
+func TestWriteReportTable(t *testing.T) {
+    r := newReport([]reportEntry{
+        {Path: "index.html", Original: 8200, Minified: 7010, Gzip: 2020, Saved: 14.51, Elapsed: 0.9},
+        {Path: "app.js", Original: 10240, Minified: 6120, Gzip: 2210, Saved: 40.23, Elapsed: 1.3},
+        {Path: "x.xml", Error: "falhou"},
+    }, 2600*time.Microsecond)
+    var b bytes.Buffer
+    if err := writeReport(&b, "table", r); err != nil {
+        t.Fatal(err)
+    }
+    lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+    if len(lines) != 5 {
+        t.Fatalf("%d linhas:\n%s", len(lines), b.String())
+    }
+    if !strings.HasPrefix(strings.TrimSpace(lines[0]), "Ficheiro  ") ||
+        !strings.HasPrefix(strings.TrimSpace(lines[1]), "app.js  ") ||
+        !strings.HasPrefix(strings.TrimSpace(lines[4]), "Total (2)") {
+        t.Errorf("tabela:\n%s", b.String())
+    }
+    // colunas alinhadas: todas as linhas com a mesma largura
+    for _, l := range lines[1:] {
+        if len([]rune(l)) != len([]rune(lines[0])) {
+            t.Errorf("linha desalinhada: %q\n%s", l, b.String())
+        }
+    }
+    for _, want := range []string{"40.2%", "18440 B", "13130 B", "erro", "2.6 ms"} {
+        if !strings.Contains(b.String(), want) {
+            t.Errorf("falta %q:\n%s", want, b.String())
+        }
+    }
+}
+
+// com -deflate, o tamanho do .deflate escrito entra no relatório
+func TestReportDeflate(t *testing.T) {
+    r := newReport([]reportEntry{
+        {Path: "a.js", Original: 1000, Minified: 600, Gzip: 300, Deflate: 290},
+        {Path: "b.js", Original: 100, Minified: 60, Gzip: 70},
+        {Path: "c.js", Error: "falhou"},
+    }, time.Millisecond)
+    if r.Total.Deflate != 290 {
+        t.Errorf("total deflate: %d", r.Total.Deflate)
+    }
+
+    var b bytes.Buffer
+    writeReport(&b, "table", r)
+    lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+    if !strings.Contains(lines[0], "gzip  deflate  Poupança") ||
+        !strings.Contains(lines[1], "290 B") || !strings.Contains(lines[2], " - ") {
+        t.Errorf("tabela:\n%s", b.String())
+    }
+    for _, l := range lines[1:] {
+        if len([]rune(l)) != len([]rune(lines[0])) {
+            t.Errorf("linha desalinhada: %q\n%s", l, b.String())
+        }
+    }
+
+    b.Reset()
+    writeReport(&b, "json", r)
+    if !strings.Contains(b.String(), `"deflate": 290`) || strings.Count(b.String(), `"deflate"`) != 2 {
+        t.Errorf("json:\n%s", b.String())
+    }
+}
@@ -0,0 +1,281 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: subcomando "minifyx serve": serviço HTTP local de minificação, para
+//          ferramentas que não são Go (PHP, scripts Python) usarem a biblioteca
+//          sem lançar um processo por ficheiro.
+//
+//              POST /minify?type=css    corpo = conteúdo; resposta = resultado
+//              POST /api/minify         JSON {"type", "content", "pretty", "options"}
+//              GET  /health             {"status": "ok", "version": ...}
+//
+//          Em /minify, sem ?type, o tipo vem do Content-Type do pedido e, se
+//          este não for conhecido (ex: text/plain), do próprio conteúdo.
+//          ?pretty=1 formata em vez de minificar. Em /api/minify, "options" tem
+//          campos de minifier.Options (ex: {"RemoveHTMLComments": false}) que
+//          substituem, só nesse pedido, as opções com que o serviço arrancou.
+//
+//          Erros: {"error": "..."} com 400 (pedido inválido), 405, 413 (corpo
+//          maior do que -max-size), 415 (tipo não suportado), 422 (XML mal
+//          formado com XMLValidate) ou 503 (mais tempo do que -timeout).
+// License: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// Content-Type das respostas de /minify, por tipo
+var serveContentTypes = map[minifier.Type]string{
+    minifier.HTML: "text/html; charset=utf-8",
+    minifier.CSS:  "text/css; charset=utf-8",
+    minifier.JS:   "text/javascript; charset=utf-8",
+    minifier.JSON: "application/json",
+    minifier.XML:  "application/xml; charset=utf-8",
+    minifier.SVG:  "image/svg+xml",
+}
+
+// runServe executa o subcomando serve e devolve o código de saída.
+func runServe(args []string, opts *minifier.Options) int {
+    var (
+        addr    string
+        maxSize int64
+        timeout time.Duration
+    )
+    fset := flag.NewFlagSet("serve", flag.ContinueOnError)
+    fset.StringVar(&addr, "addr", "127.0.0.1:8080", "Endereço onde escutar (por omissão só local)")
+    fset.Int64Var(&maxSize, "max-size", 10<<20, "Tamanho máximo do corpo de um pedido, em bytes")
+    fset.DurationVar(&timeout, "timeout", 30*time.Second, "Tempo máximo de um pedido (leitura, minificação e resposta)")
+    fset.Usage = func() {
+        fmt.Fprintln(fset.Output(), "Uso: minifyx [opções de minificação] serve [-addr 127.0.0.1:8080] [-max-size 10485760] [-timeout 30s]")
+        fset.PrintDefaults()
+    }
+    if err := fset.Parse(args); err != nil {
+        return exitUsage
+    }
+    if fset.NArg() != 0 {
+        fset.Usage()
+        return exitUsage
+    }
+    if maxSize < 1 || timeout <= 0 {
+        fmt.Fprintln(os.Stderr, "-max-size e -timeout têm de ser positivos")
+        return exitUsage
+    }
+
+    srv := &http.Server{
+        Addr:              addr,
+        Handler:           newServeHandler(opts, maxSize, timeout),
+        ReadHeaderTimeout: 10 * time.Second,
+        ReadTimeout:       timeout,
+        // a resposta de um pedido que esgotou o tempo ainda tem de poder sair
+        WriteTimeout: timeout + 5*time.Second,
+        IdleTimeout:  time.Minute,
+    }
+
+    // Ctrl+C / SIGTERM: deixar terminar os pedidos em curso
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    errc := make(chan error, 1)
+    go func() { errc <- srv.ListenAndServe() }()
+    fmt.Fprintf(os.Stderr, "minifyx serve: a escutar em http://%s\n", addr)
+
+    select {
+    case err := <-errc:
+        fmt.Fprintln(os.Stderr, "Erro:", err)
+        return exitFailed
+    case <-ctx.Done():
+    }
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    if err := srv.Shutdown(shutdownCtx); err != nil {
+        fmt.Fprintln(os.Stderr, "Erro ao terminar:", err)
+        return exitFailed
+    }
+    return exitOK
+}
+
+// newServeHandler devolve o handler do serviço, com opts como opções base.
+func newServeHandler(opts *minifier.Options, maxSize int64, timeout time.Duration) http.Handler {
+    s := &server{opts: opts, maxSize: maxSize}
+    timeoutBody := `{"error":"tempo esgotado"}`
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/health", s.health)
+    mux.Handle("/minify", http.TimeoutHandler(http.HandlerFunc(s.minify), timeout, timeoutBody))
+    mux.Handle("/api/minify", http.TimeoutHandler(http.HandlerFunc(s.apiMinify), timeout, timeoutBody))
+    return mux
+}
+
+type server struct {
+    opts    *minifier.Options
+    maxSize int64
+}
+
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet && r.Method != http.MethodHead {
+        serveError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+        return
+    }
+    writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": minifier.ResolvedVersion()})
+}
+
+// minify trata POST /minify: o corpo é o conteúdo e a resposta o resultado.
+func (s *server) minify(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        serveError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+        return
+    }
+    body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxSize))
+    if err != nil {
+        serveError(w, readErrorStatus(err), err)
+        return
+    }
+
+    q := r.URL.Query()
+    t := typeByName(q.Get("type"))
+    if q.Get("type") == "" {
+        t = minifier.TypeForMIME(r.Header.Get("Content-Type"))
+        if t == minifier.ERROR {
+            t = minifier.SniffType(body)
+        }
+    }
+    if t == minifier.ERROR {
+        serveError(w, http.StatusUnsupportedMediaType, errors.New("tipo não suportado (use ?type=html|css|js|json|xml|svg)"))
+        return
+    }
+
+    out, err := process(string(body), t, q.Get("pretty") == "1" || q.Get("pretty") == "true", s.opts)
+    if err != nil {
+        serveError(w, processErrorStatus(err), err)
+        return
+    }
+    w.Header().Set("Content-Type", serveContentTypes[t])
+    io.WriteString(w, out)
+}
+
+// apiRequest e apiResponse são o corpo do pedido e da resposta de /api/minify.
+type apiRequest struct {
+    Type    string          `json:"type"`
+    Content string          `json:"content"`
+    Pretty  bool            `json:"pretty"`
+    Options json.RawMessage `json:"options"`
+}
+
+type apiResponse struct {
+    Type     string `json:"type"`
+    Output   string `json:"output"`
+    Original int    `json:"original"`
+    Minified int    `json:"minified"`
+}
+
+// apiMinify trata POST /api/minify (pedido e resposta em JSON).
+func (s *server) apiMinify(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        serveError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+        return
+    }
+    var req apiRequest
+    dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxSize))
+    dec.DisallowUnknownFields()
+    if err := dec.Decode(&req); err != nil {
+        serveError(w, readErrorStatus(err), err)
+        return
+    }
+
+    t := typeByName(req.Type)
+    if req.Type == "" {
+        t = minifier.SniffType([]byte(req.Content))
+    }
+    if t == minifier.ERROR {
+        serveError(w, http.StatusUnsupportedMediaType, errors.New("tipo não suportado (use \"type\": html|css|js|json|xml|svg)"))
+        return
+    }
+    opts, err := requestOptions(s.opts, req.Options)
+    if err != nil {
+        serveError(w, http.StatusBadRequest, fmt.Errorf("options: %w", err))
+        return
+    }
+
+    out, err := process(req.Content, t, req.Pretty, opts)
+    if err != nil {
+        serveError(w, processErrorStatus(err), err)
+        return
+    }
+    writeJSON(w, http.StatusOK, apiResponse{
+        Type:     typeName(t),
+        Output:   out,
+        Original: len(req.Content),
+        Minified: len(out),
+    })
+}
+
+// requestOptions devolve uma cópia de base com os campos de raw (JSON com nomes
+// de campos de minifier.Options). base nunca é alterado.
+func requestOptions(base *minifier.Options, raw json.RawMessage) (*minifier.Options, error) {
+    if len(raw) == 0 || string(raw) == "null" {
+        return base, nil
+    }
+    opts := *base
+    // o json reutiliza o array de um slice existente: copiar para não mexer no de base
+    opts.XMLPreserveWhitespaceElements = slices.Clone(base.XMLPreserveWhitespaceElements)
+    opts.XMLC14NInclusivePrefixes = slices.Clone(base.XMLC14NInclusivePrefixes)
+
+    dec := json.NewDecoder(bytes.NewReader(raw))
+    dec.DisallowUnknownFields()
+    if err := dec.Decode(&opts); err != nil {
+        return nil, err
+    }
+    // o registo de minificadores não vem do pedido
+    opts.Registry = base.Registry
+    return &opts, nil
+}
+
+func process(input string, t minifier.Type, pretty bool, opts *minifier.Options) (string, error) {
+    if pretty {
+        return minifier.Beautify(input, t, opts)
+    }
+    return minifier.Minify(input, t, opts)
+}
+
+// readErrorStatus devolve o código HTTP de um erro a ler o corpo do pedido.
+func readErrorStatus(err error) int {
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+        return http.StatusRequestEntityTooLarge
+    }
+    return http.StatusBadRequest
+}
+
+// processErrorStatus devolve o código HTTP de um erro de minificação.
+func processErrorStatus(err error) int {
+    if exitCodeFor(err) == exitValidation {
+        return http.StatusUnprocessableEntity
+    }
+    return http.StatusInternalServerError
+}
+
+func serveError(w http.ResponseWriter, status int, err error) {
+    writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    enc := json.NewEncoder(w)
+    enc.SetEscapeHTML(false) // o output é HTML/XML: sem \u003c
+    enc.Encode(v)
+}
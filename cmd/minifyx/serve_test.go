@@ -0,0 +1,196 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o serviço HTTP (minifyx serve)
+// License: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// serveRequest faz um pedido ao handler e devolve a resposta.
+func serveRequest(h http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+    req := httptest.NewRequest(method, target, strings.NewReader(body))
+    if contentType != "" {
+        req.Header.Set("Content-Type", contentType)
+    }
+    rec := httptest.NewRecorder()
+    h.ServeHTTP(rec, req)
+    return rec
+}
+
+func TestServeMinify(t *testing.T) {
+    h := newServeHandler(minifier.DefaultOptions(), 1<<20, 5*time.Second)
+    tests := []struct {
+        name        string
+        target      string
+        contentType string
+        body        string
+        expected    string
+        respType    string
+    }{
+        {"?type", "/minify?type=css", "", "body {\n  color: red;\n}", "body{color:red;}", "text/css; charset=utf-8"},
+        {"?type ganha ao Content-Type", "/minify?type=js", "text/css", "var a = 1;", "var a=1;", "text/javascript; charset=utf-8"},
+        {"Content-Type", "/minify", "application/problem+json; charset=utf-8", `{ "a" : 1 }`, `{"a":1}`, "application/json"},
+        {"Content-Type svg", "/minify", "image/svg+xml", "<svg xmlns=\"http://www.w3.org/2000/svg\">\n  <g/>\n</svg>", "", "image/svg+xml"},
+        {"pelo conteúdo", "/minify", "text/plain", "<!DOCTYPE html>\n<p>  a  </p>", "<!DOCTYPE html><p> a</p>", "text/html; charset=utf-8"},
+        {"sem Content-Type", "/minify", "", "[1, 2]", "[1,2]", "application/json"},
+        {"pretty", "/minify?type=json&pretty=1", "", `{"a":1}`, "{\n  \"a\": 1\n}", "application/json"},
+    }
+    for _, tt := range tests {
+        rec := serveRequest(h, http.MethodPost, tt.target, tt.contentType, tt.body)
+        if rec.Code != http.StatusOK {
+            t.Errorf("%s: código %d (%s)", tt.name, rec.Code, rec.Body.String())
+            continue
+        }
+        if tt.expected != "" && rec.Body.String() != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.name, rec.Body.String(), tt.expected)
+        }
+        if got := rec.Header().Get("Content-Type"); got != tt.respType {
+            t.Errorf("%s: Content-Type %q, want %q", tt.name, got, tt.respType)
+        }
+    }
+}
+
+func TestServeErrors(t *testing.T) {
+    opts := minifier.DefaultOptions()
+    opts.XMLValidate = true
+    h := newServeHandler(opts, 64, 5*time.Second)
+    tests := []struct {
+        name     string
+        method   string
+        target   string
+        ctype    string
+        body     string
+        expected int
+    }{
+        {"corpo grande", http.MethodPost, "/minify?type=css", "", strings.Repeat("a", 65), http.StatusRequestEntityTooLarge},
+        {"corpo no limite", http.MethodPost, "/minify?type=css", "", strings.Repeat("a", 64), http.StatusOK},
+        {"api corpo grande", http.MethodPost, "/api/minify", "", `{"type":"css","content":"` + strings.Repeat("a", 64) + `"}`, http.StatusRequestEntityTooLarge},
+        {"tipo desconhecido", http.MethodPost, "/minify", "text/plain", "Olá mundo", http.StatusUnsupportedMediaType},
+        {"?type inválido", http.MethodPost, "/minify?type=png", "", "a{}", http.StatusUnsupportedMediaType},
+        {"api tipo inválido", http.MethodPost, "/api/minify", "", `{"type":"png","content":"x"}`, http.StatusUnsupportedMediaType},
+        {"XML mal formado", http.MethodPost, "/minify?type=xml", "", "<a><b></a>", http.StatusUnprocessableEntity},
+        {"GET /minify", http.MethodGet, "/minify", "", "", http.StatusMethodNotAllowed},
+        {"GET /api/minify", http.MethodGet, "/api/minify", "", "", http.StatusMethodNotAllowed},
+        {"POST /health", http.MethodPost, "/health", "", "", http.StatusMethodNotAllowed},
+        {"JSON inválido", http.MethodPost, "/api/minify", "", `{"type":`, http.StatusBadRequest},
+        {"campo desconhecido", http.MethodPost, "/api/minify", "", `{"tipo":"css"}`, http.StatusBadRequest},
+        {"opção desconhecida", http.MethodPost, "/api/minify", "", `{"type":"css","options":{"Nada":true}}`, http.StatusBadRequest},
+    }
+    for _, tt := range tests {
+        rec := serveRequest(h, tt.method, tt.target, tt.ctype, tt.body)
+        if rec.Code != tt.expected {
+            t.Errorf("%s: código %d, want %d (%s)", tt.name, rec.Code, tt.expected, rec.Body.String())
+            continue
+        }
+        if tt.expected == http.StatusOK {
+            continue
+        }
+        var body struct{ Error string }
+        if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+            t.Errorf("%s: corpo do erro %q", tt.name, rec.Body.String())
+        }
+        if got := rec.Header().Get("Content-Type"); got != "application/json" {
+            t.Errorf("%s: Content-Type %q", tt.name, got)
+        }
+    }
+}
+
+func TestServeAPIOptions(t *testing.T) {
+    base := minifier.DefaultOptions()
+    base.MinifyHTMLEntities = true
+    want := *base
+    want.XMLPreserveWhitespaceElements = append([]string(nil), base.XMLPreserveWhitespaceElements...)
+    h := newServeHandler(base, 1<<20, 5*time.Second)
+
+    post := func(body string) apiResponse {
+        t.Helper()
+        rec := serveRequest(h, http.MethodPost, "/api/minify", "application/json", body)
+        if rec.Code != http.StatusOK {
+            t.Fatalf("código %d: %s", rec.Code, rec.Body.String())
+        }
+        var resp apiResponse
+        if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+            t.Fatal(err)
+        }
+        return resp
+    }
+
+    // opções base do serviço
+    resp := post(`{"type":"html","content":"<p>&eacute;</p><!-- c -->"}`)
+    if resp.Output != "<p>é</p>" || resp.Type != "html" || resp.Original != len("<p>&eacute;</p><!-- c -->") || resp.Minified != len("<p>é</p>") {
+        t.Errorf("base: %+v", resp)
+    }
+    // opções do pedido juntam-se às base
+    resp = post(`{"type":"html","content":"<p>&eacute;</p><!-- c -->","options":{"RemoveHTMLComments":false}}`)
+    if resp.Output != "<p>é</p><!-- c -->" {
+        t.Errorf("com options: %q", resp.Output)
+    }
+    // slices substituídos no pedido não mexem nos da base
+    resp = post(`{"type":"xml","content":"<a><b> x </b></a>","options":{"XMLPreserveWhitespaceElements":["b"]}}`)
+    if resp.Output != "<a><b> x </b></a>" {
+        t.Errorf("slice: %q", resp.Output)
+    }
+    // sem "type", pelo conteúdo; e o pedido seguinte volta às opções base
+    resp = post(`{"content":"<p>&eacute;</p><!-- c -->"}`)
+    if resp.Type != "html" || resp.Output != "<p>é</p>" {
+        t.Errorf("depois: %+v", resp)
+    }
+    if !reflect.DeepEqual(*base, want) {
+        t.Errorf("opções base alteradas:\n%+v\n%+v", *base, want)
+    }
+}
+
+// pedidos em paralelo com opções diferentes (com -race: sem partilha de estado)
+func TestServeAPIOptionsConcurrent(t *testing.T) {
+    h := newServeHandler(minifier.DefaultOptions(), 1<<20, 5*time.Second)
+    var wg sync.WaitGroup
+    for i := 0; i < 20; i++ {
+        wg.Add(1)
+        go func(keep bool) {
+            defer wg.Done()
+            body := `{"type":"html","content":"<p>a</p><!-- c -->","options":{"RemoveHTMLComments":false,"XMLPreserveWhitespaceElements":["x"]}}`
+            want := "<p>a</p><!-- c -->"
+            if !keep {
+                body = `{"type":"html","content":"<p>a</p><!-- c -->"}`
+                want = "<p>a</p>"
+            }
+            rec := serveRequest(h, http.MethodPost, "/api/minify", "", body)
+            var resp apiResponse
+            json.Unmarshal(rec.Body.Bytes(), &resp)
+            if resp.Output != want {
+                t.Errorf("got %q, want %q", resp.Output, want)
+            }
+        }(i%2 == 0)
+    }
+    wg.Wait()
+}
+
+func TestServeHealth(t *testing.T) {
+    h := newServeHandler(minifier.DefaultOptions(), 1<<20, 5*time.Second)
+    for _, method := range []string{http.MethodGet, http.MethodHead} {
+        rec := serveRequest(h, method, "/health", "", "")
+        if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+            t.Errorf("%s: código %d, Content-Type %q", method, rec.Code, rec.Header().Get("Content-Type"))
+        }
+    }
+    rec := serveRequest(h, http.MethodGet, "/health", "", "")
+    var body map[string]string
+    if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+        t.Fatal(err)
+    }
+    if body["status"] != "ok" || body["version"] != minifier.ResolvedVersion() {
+        t.Errorf("got %v", body)
+    }
+}
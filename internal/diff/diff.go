@@ -0,0 +1,238 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: diff de linhas (algoritmo de Myers) e formato unificado (como o
+//          "diff -u"), usado pela CLI em -diff. Sem dependências externas.
+// License: MIT
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// acima deste número de diferenças desiste de procurar o diff mínimo e mostra o
+// bloco do meio como apagado + inserido (o custo do Myers é O(D²) em memória)
+const maxEditDistance = 4000
+
+// Op é o tipo de uma linha do diff.
+type Op int8
+
+const (
+    Equal Op = iota
+    Delete
+    Insert
+)
+
+// Edit é uma linha do diff: igual nos dois lados, só no antigo ou só no novo.
+type Edit struct {
+    Op   Op
+    Text string // linha, com o "\n" final se o tiver
+}
+
+// Lines devolve as edições que transformam as linhas a nas linhas b.
+func Lines(a, b []string) []Edit {
+    // prefixo e sufixo comuns ficam fora do Myers
+    pre := 0
+    for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+        pre++
+    }
+    suf := 0
+    for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+        suf++
+    }
+
+    edits := make([]Edit, 0, len(a)+len(b))
+    for _, l := range a[:pre] {
+        edits = append(edits, Edit{Equal, l})
+    }
+    edits = append(edits, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+    for _, l := range a[len(a)-suf:] {
+        edits = append(edits, Edit{Equal, l})
+    }
+    return edits
+}
+
+// myers é o algoritmo O(ND) de Myers, com o caminho reconstruído a partir das
+// fronteiras guardadas em cada passo.
+func myers(a, b []string) []Edit {
+    n, m := len(a), len(b)
+    if n == 0 || m == 0 {
+        return replaceAll(a, b)
+    }
+    max := n + m
+    off := max + 1
+    v := make([]int, 2*max+3)
+    var trace [][]int // trace[d] = fronteira (k = -d..d) antes do passo d
+
+    for d := 0; d <= max; d++ {
+        if d > maxEditDistance {
+            return replaceAll(a, b)
+        }
+        trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+        for k := -d; k <= d; k += 2 {
+            var x int
+            if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+                x = v[off+k+1] // desce: inserção
+            } else {
+                x = v[off+k-1] + 1 // direita: remoção
+            }
+            y := x - k
+            for x < n && y < m && a[x] == b[y] {
+                x++
+                y++
+            }
+            v[off+k] = x
+            if x >= n && y >= m {
+                return backtrack(a, b, trace, d)
+            }
+        }
+    }
+    return replaceAll(a, b)
+}
+
+func backtrack(a, b []string, trace [][]int, dist int) []Edit {
+    var rev []Edit
+    x, y := len(a), len(b)
+    for d := dist; d > 0; d-- {
+        v := trace[d] // índice k+d
+        k := x - y
+        var prevK int
+        if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+            prevK = k + 1
+        } else {
+            prevK = k - 1
+        }
+        prevX := v[prevK+d]
+        prevY := prevX - prevK
+        for x > prevX && y > prevY {
+            x--
+            y--
+            rev = append(rev, Edit{Equal, a[x]})
+        }
+        if x == prevX {
+            y--
+            rev = append(rev, Edit{Insert, b[y]})
+        } else {
+            x--
+            rev = append(rev, Edit{Delete, a[x]})
+        }
+    }
+    for x > 0 && y > 0 {
+        x--
+        y--
+        rev = append(rev, Edit{Equal, a[x]})
+    }
+
+    edits := make([]Edit, len(rev))
+    for i, e := range rev {
+        edits[len(rev)-1-i] = e
+    }
+    return edits
+}
+
+func replaceAll(a, b []string) []Edit {
+    edits := make([]Edit, 0, len(a)+len(b))
+    for _, l := range a {
+        edits = append(edits, Edit{Delete, l})
+    }
+    for _, l := range b {
+        edits = append(edits, Edit{Insert, l})
+    }
+    return edits
+}
+
+// SplitLines divide s em linhas, cada uma com o seu "\n" (a última pode não ter).
+func SplitLines(s string) []string {
+    if s == "" {
+        return nil
+    }
+    lines := strings.SplitAfter(s, "\n")
+    if lines[len(lines)-1] == "" {
+        lines = lines[:len(lines)-1]
+    }
+    return lines
+}
+
+// Unified devolve o diff unificado de oldText para newText, com context linhas
+// de contexto à volta de cada alteração ("" se forem iguais).
+func Unified(oldName, newName, oldText, newText string, context int) string {
+    if oldText == newText {
+        return ""
+    }
+    edits := Lines(SplitLines(oldText), SplitLines(newText))
+
+    var sb strings.Builder
+    fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+    // posição (0-based) de cada edição nos dois lados
+    type pos struct{ a, b int }
+    at := make([]pos, len(edits)+1)
+    for i, e := range edits {
+        at[i+1] = at[i]
+        if e.Op != Insert {
+            at[i+1].a++
+        }
+        if e.Op != Delete {
+            at[i+1].b++
+        }
+    }
+
+    for i := 0; i < len(edits); {
+        if edits[i].Op == Equal {
+            i++
+            continue
+        }
+        // hunk: desde context linhas antes da alteração até context linhas
+        // depois da última alteração a menos de 2*context linhas iguais
+        start := max(i-context, 0)
+        end := i
+        for end < len(edits) {
+            if edits[end].Op != Equal {
+                end++
+                continue
+            }
+            run := end
+            for run < len(edits) && edits[run].Op == Equal {
+                run++
+            }
+            if run == len(edits) || run-end > 2*context {
+                end = min(end+context, len(edits))
+                break
+            }
+            end = run
+        }
+
+        oldStart, oldLen := at[start].a, at[end].a-at[start].a
+        newStart, newLen := at[start].b, at[end].b-at[start].b
+        fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+        for _, e := range edits[start:end] {
+            switch e.Op {
+            case Equal:
+                sb.WriteByte(' ')
+            case Delete:
+                sb.WriteByte('-')
+            case Insert:
+                sb.WriteByte('+')
+            }
+            sb.WriteString(e.Text)
+            if !strings.HasSuffix(e.Text, "\n") {
+                sb.WriteString("\n\\ No newline at end of file\n")
+            }
+        }
+        i = end
+    }
+    return sb.String()
+}
+
+// hunkRange formata "início,tamanho" como o diff -u (início 1-based; com
+// tamanho 0 é a linha antes da posição).
+func hunkRange(start, length int) string {
+    if length == 0 {
+        return fmt.Sprintf("%d,0", start)
+    }
+    if length == 1 {
+        return fmt.Sprintf("%d", start+1)
+    }
+    return fmt.Sprintf("%d,%d", start+1, length)
+}
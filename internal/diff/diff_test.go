@@ -0,0 +1,118 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o diff de linhas (Myers) e o formato unificado
+// License: MIT
+
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+    tests := []struct {
+        name     string
+        old, new string
+        context  int
+        expected string
+    }{
+        {"iguais", "a\nb\n", "a\nb\n", 3, ""},
+        {
+            "alteração",
+            "a\nb\nc\n", "a\nB\nc\n", 3,
+            "--- antigo\n+++ novo\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+        },
+        {
+            "vazio",
+            "", "a\n", 3,
+            "--- antigo\n+++ novo\n@@ -0,0 +1 @@\n+a\n",
+        },
+        {
+            "sem newline no fim",
+            "a\nb", "a\nc", 3,
+            "--- antigo\n+++ novo\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+        },
+        {
+            "dois hunks",
+            "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n", 0,
+            "--- antigo\n+++ novo\n@@ -0,0 +1 @@\n+0\n@@ -10 +10,0 @@\n-10\n",
+        },
+    }
+    for _, tt := range tests {
+        got := Unified("antigo", "novo", tt.old, tt.new, tt.context)
+        if got != tt.expected {
+            t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tt.name, got, tt.expected)
+        }
+    }
+}
+
+func TestUnifiedContext(t *testing.T) {
+    old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+    new := "1\n2\nx\n4\n5\n6\n7\n8\ny\n10\n"
+
+    // a 5 linhas de distância: com contexto 3 fica um só hunk
+    got := Unified("a", "b", old, new, 3)
+    if strings.Count(got, "@@ -") != 1 || !strings.Contains(got, "@@ -1,10 +1,10 @@") {
+        t.Errorf("contexto 3:\n%s", got)
+    }
+    // com contexto 1 ficam dois
+    got = Unified("a", "b", old, new, 1)
+    if !strings.Contains(got, "@@ -2,3 +2,3 @@") || !strings.Contains(got, "@@ -8,3 +8,3 @@") {
+        t.Errorf("contexto 1:\n%s", got)
+    }
+}
+
+// o diff tem de reconstruir os dois lados e ser mínimo (LCS)
+func TestLinesRandom(t *testing.T) {
+    rnd := rand.New(rand.NewSource(1))
+    words := []string{"a\n", "b\n", "c\n", "d\n"}
+    gen := func() []string {
+        l := make([]string, rnd.Intn(30))
+        for i := range l {
+            l[i] = words[rnd.Intn(len(words))]
+        }
+        return l
+    }
+    for n := 0; n < 500; n++ {
+        a, b := gen(), gen()
+        edits := Lines(a, b)
+        var gotA, gotB []string
+        equal := 0
+        for _, e := range edits {
+            if e.Op != Insert {
+                gotA = append(gotA, e.Text)
+            }
+            if e.Op != Delete {
+                gotB = append(gotB, e.Text)
+            }
+            if e.Op == Equal {
+                equal++
+            }
+        }
+        if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+            t.Fatalf("não reconstrói: %q / %q", a, b)
+        }
+        if lcs := lcsLen(a, b); equal != lcs {
+            t.Fatalf("não é mínimo: %d iguais, LCS %d (%q / %q)", equal, lcs, a, b)
+        }
+    }
+}
+
+func lcsLen(a, b []string) int {
+    dp := make([][]int, len(a)+1)
+    for i := range dp {
+        dp[i] = make([]int, len(b)+1)
+    }
+    for i := len(a) - 1; i >= 0; i-- {
+        for j := len(b) - 1; j >= 0; j-- {
+            if a[i] == b[j] {
+                dp[i][j] = dp[i+1][j+1] + 1
+            } else {
+                dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+            }
+        }
+    }
+    return dp[0][0]
+}
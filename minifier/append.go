@@ -0,0 +1,87 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: variantes []byte → []byte dos minificadores, que acrescentam o
+//          resultado a um slice do chamador (estilo strconv.AppendInt). Com um
+//          destino reutilizado (ex: sync.Pool) JSON, XML e CSS não alocam nada
+//          por chamada além do que o próprio documento exige. AppendJS,
+//          AppendHTML e AppendSVG são só atalhos sobre as versões string.
+// License: MIT
+
+package minifier
+
+import "errors"
+
+// AppendMinify minifica src conforme o tipo e acrescenta o resultado a dst.
+func AppendMinify(dst, src []byte, t Type, opts *Options) ([]byte, error) {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    switch t {
+    case HTML:
+        return AppendHTML(dst, src, opts), nil
+    case CSS:
+        return AppendCSS(dst, src), nil
+    case JS:
+        return AppendJS(dst, src), nil
+    case JSON:
+        return AppendJSON(dst, src, opts), nil
+    case XML, SVG:
+        if opts.XMLValidate {
+            if err := ValidateXML(string(src)); err != nil { return dst, err }
+        }
+        if t == SVG {
+            return AppendSVG(dst, src, opts), nil
+        }
+        return AppendXML(dst, src, opts), nil
+    default:
+        return dst, errors.New("tipo não suportado")
+    }
+}
+
+// AppendJSON é a versão []byte de MinifyJSONWithOptions.
+func AppendJSON(dst, src []byte, opts *Options) []byte {
+    s := newBytesStream(dst, src)
+    defer s.release()
+    minifyJSONStream(s, opts)
+    return s.out
+}
+
+// AppendCSS é a versão []byte de MinifyCSS.
+func AppendCSS(dst, src []byte) []byte {
+    s := newBytesStream(dst, src)
+    defer s.release()
+    minifyCSSStream(s)
+    return s.out
+}
+
+// AppendXML é a versão []byte de MinifyXML.
+func AppendXML(dst, src []byte, opts *Options) []byte {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    if opts.XMLCleanNamespaces || opts.XMLShortenPrefixes {
+        src = []byte(cleanXMLNamespaces(string(src), opts.XMLShortenPrefixes))
+    }
+    s := newBytesStream(dst, src)
+    defer s.release()
+    minifyXMLStream(s, opts)
+    return s.out
+}
+
+// AppendJS chama MinifyJS e acrescenta o resultado a dst: é só um atalho, com
+// as cópias da conversão para string (não há caminho []byte para JS).
+func AppendJS(dst, src []byte) []byte {
+    return append(dst, MinifyJS(string(src))...)
+}
+
+// AppendHTML chama MinifyHTML e acrescenta o resultado a dst (atalho, como
+// AppendJS).
+func AppendHTML(dst, src []byte, opts *Options) []byte {
+    return append(dst, MinifyHTML(string(src), opts)...)
+}
+
+// AppendSVG chama MinifySVG e acrescenta o resultado a dst (atalho, como
+// AppendJS).
+func AppendSVG(dst, src []byte, opts *Options) []byte {
+    return append(dst, MinifySVG(string(src), opts)...)
+}
@@ -0,0 +1,95 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para as APIs []byte (Append*) e benchmarks de alocações
+// License: MIT
+
+package minifier
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+var appendDocs = []struct {
+    name  string
+    t     Type
+    input string
+}{
+    {"HTML", HTML, "<html>\n  <body>\n    <p class=\"x\">Olá   mundo</p>\n    <style>a { color: red; }</style>\n  </body>\n</html>"},
+    {"CSS", CSS, "body {\n  margin : 0 ;\n  color: #fff; /* comentário */\n}\n.a , .b { padding: 0 1px }\n"},
+    {"JS", JS, "function soma(a, b) {\n  // comentário\n  return a + b;\n}\n"},
+    {"JSON", JSON, "{\n  \"nome\": \"João\",\n  \"lista\": [ 1, 2, 3 ],\n  \"obj\": { \"a\": true }\n}"},
+    {"XML", XML, "<?xml version=\"1.0\"?>\n<root>\n  <!-- c -->\n  <item id=\"1\">  texto  </item>\n  <vazio></vazio>\n</root>"},
+    {"SVG", SVG, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 10 10\">\n  <path d=\"M 0 0 L 10 0 L 10 10 Z\"/>\n</svg>"},
+}
+
+func TestAppendMatchesString(t *testing.T) {
+    opts := DefaultOptions()
+    for _, d := range appendDocs {
+        want, err := Minify(d.input, d.t, opts)
+        if err != nil {
+            t.Fatal(err)
+        }
+        got, err := AppendMinify([]byte("prefixo:"), []byte(d.input), d.t, opts)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if string(got) != "prefixo:"+want {
+            t.Errorf("%s: got %q, want %q", d.name, got, "prefixo:"+want)
+        }
+    }
+}
+
+// Com o destino reutilizado, JSON, XML e CSS não alocam por chamada.
+func TestAppendNoAllocs(t *testing.T) {
+    if raceEnabled {
+        t.Skip("com -race o sync.Pool descarta buffers")
+    }
+    opts := DefaultOptions()
+    for _, d := range appendDocs {
+        if d.t != JSON && d.t != XML && d.t != CSS {
+            continue
+        }
+        src := []byte(d.input)
+        dst := make([]byte, 0, 1024)
+        allocs := testing.AllocsPerRun(100, func() {
+            dst, _ = AppendMinify(dst[:0], src, d.t, opts)
+        })
+        if allocs > 0 {
+            t.Errorf("%s: %.1f alocações por chamada", d.name, allocs)
+        }
+    }
+}
+
+var benchBufPool = sync.Pool{
+    New: func() any { b := make([]byte, 0, 4096); return &b },
+}
+
+func BenchmarkAppendMinify(b *testing.B) {
+    opts := DefaultOptions()
+    for _, d := range appendDocs {
+        input := strings.Repeat(d.input, 20)
+        if d.t == XML || d.t == SVG || d.t == JSON {
+            input = d.input // documentos com uma única raiz
+        }
+        src := []byte(input)
+
+        b.Run(d.name+"/string", func(b *testing.B) {
+            b.ReportAllocs()
+            b.SetBytes(int64(len(src)))
+            for i := 0; i < b.N; i++ {
+                Minify(input, d.t, opts)
+            }
+        })
+        b.Run(d.name+"/append", func(b *testing.B) {
+            b.ReportAllocs()
+            b.SetBytes(int64(len(src)))
+            for i := 0; i < b.N; i++ {
+                buf := benchBufPool.Get().(*[]byte)
+                *buf, _ = AppendMinify((*buf)[:0], src, d.t, opts)
+                benchBufPool.Put(buf)
+            }
+        })
+    }
+}
@@ -2,6 +2,7 @@
 // Date: 2025-12-15
 // Purpose: MinifyCSS faz uma minificação conservadora de CSS
 //          remove comentários de bloco fora de strings e aperta espaços supérfluos
+//          (o trabalho é feito por minifyCSSStream, partilhado com MinifyCSSStream)
 // License: MIT
 
 package minifier
@@ -12,6 +13,12 @@ import (
 
 func MinifyCSS(input string) string {
     var out strings.Builder
+    MinifyCSSStream(&out, strings.NewReader(input))
+    return out.String()
+}
+
+// minifyCSSStream é o minificador de CSS propriamente dito (ver stream.go).
+func minifyCSSStream(s *stream) error {
     inBlockComment := false
     inString := false
     inURL := false
@@ -19,23 +26,35 @@ func MinifyCSS(input string) string {
     stringQuote := byte(0)
     lastSpaceOutsideString := false
 
-    b := []byte(input)
+    // Apertar espaços em torno de símbolos onde é seguro: um espaço fora de
+    // strings só é escrito quando chega o caractere seguinte e nenhum dos dois
+    // lados é um destes símbolos (espaços no início/fim desaparecem).
+    pendingSpace := false
+    last := byte(0) // último byte escrito (0 = nada ainda)
+    write := func(c byte) {
+        if pendingSpace {
+            if last != 0 && !isCSSTightChar(last) && !isCSSTightChar(c) {
+                s.writeByte(' ')
+            }
+            pendingSpace = false
+        }
+        s.writeByte(c)
+        last = c
+    }
 
-    for i := 0; i < len(b); i++ {
-        c := b[i]
-        var next byte
-        if i+1 < len(b) {
-            next = b[i+1]
+    for {
+        c, err := s.readByte()
+        if err != nil {
+            return err
         }
 
         // Detetar contexto url(...) (case-insensitive) fora de strings/comentários
         if !inString && !inBlockComment {
-            if !inURL && (c == 'u' || c == 'U') && i+3 < len(b) {
-                u := b[i : i+3]
-                if (u[0] == 'u' || u[0] == 'U') &&
-                    (u[1] == 'r' || u[1] == 'R') &&
-                    (u[2] == 'l' || u[2] == 'L') &&
-                    b[i+3] == '(' {
+            if !inURL && (c == 'u' || c == 'U') {
+                if p := s.peek(3); len(p) == 3 &&
+                    (p[0] == 'r' || p[0] == 'R') &&
+                    (p[1] == 'l' || p[1] == 'L') &&
+                    p[2] == '(' {
                     inURL = true
                     urlParenDepth = 0
                 }
@@ -57,25 +76,25 @@ func MinifyCSS(input string) string {
         }
 
         if inBlockComment {
-            if c == '*' && next == '/' {
+            if c == '*' && s.hasPrefix("/") {
                 inBlockComment = false
-                i++
+                s.skip(1)
             }
             continue
         }
 
         // Início de comentário de bloco (mas preserva /*! ... */)
-        if !inString && !inURL && c == '/' && next == '*' {
-            if i+2 < len(b) && b[i+2] == '!' {
+        if !inString && !inURL && c == '/' && s.hasPrefix("*") {
+            if s.hasPrefix("*!") {
                 // comentário do tipo /*! ... */ é mantido
-                out.WriteByte(c)
-                out.WriteByte(next)
-                i++
+                write(c)
+                write('*')
+                s.skip(1)
                 lastSpaceOutsideString = false
                 continue
             }
             inBlockComment = true
-            i++
+            s.skip(1)
             continue
         }
 
@@ -83,17 +102,16 @@ func MinifyCSS(input string) string {
         if !inString && (c == '\'' || c == '"') {
             inString = true
             stringQuote = c
-            out.WriteByte(c)
+            write(c)
             lastSpaceOutsideString = false
             continue
         }
 
         if inString {
-            out.WriteByte(c)
+            write(c)
             if c == '\\' {
-                if i+1 < len(b) {
-                    out.WriteByte(b[i+1])
-                    i++
+                if n, err := s.readByte(); err == nil {
+                    write(n)
                 }
                 continue
             }
@@ -114,32 +132,22 @@ func MinifyCSS(input string) string {
             if lastSpaceOutsideString {
                 continue // já temos um espaço, não precisamos de outro
             }
-            out.WriteByte(' ')
+            pendingSpace = true
             lastSpaceOutsideString = true
             continue
         }
 
         // Qualquer outro caractere “normal”
-        out.WriteByte(c)
+        write(c)
         lastSpaceOutsideString = false
     }
+}
 
-    s := out.String()
-    s = strings.TrimSpace(s)
-
-    // Apertar espaços em torno de símbolos onde é seguro
-    replacers := []struct{ old, new string }{
-        {" ;", ";"}, {"; ", ";"},
-        {" :", ":"}, {": ", ":"},
-        {" ,", ","}, {", ", ","},
-        {" {", "{"}, {"{ ", "{"},
-        {" }", "}"}, {"} ", "}"},
-        {" (", "("}, {"( ", "("},
-        {" )", ")"}, {") ", ")"},
+// isCSSTightChar indica os símbolos à volta dos quais os espaços são supérfluos.
+func isCSSTightChar(c byte) bool {
+    switch c {
+    case ';', ':', ',', '{', '}', '(', ')':
+        return true
     }
-    for _, r := range replacers {
-        s = strings.ReplaceAll(s, r.old, r.new)
-    }
-
-    return s
+    return false
 }
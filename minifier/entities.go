@@ -0,0 +1,291 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: minimização de referências a caracteres/entidades (&#233;, &eacute;,
+//          &#x20AC; ...) em XML e HTML:
+//          - a referência passa ao carácter em UTF-8 quando este não precisa de
+//            escape no contexto (texto, atributo com aspas "/', atributo sem aspas)
+//          - as que têm de ficar escapadas passam à forma mais curta
+//            (&lt; &gt; &amp;, ou &#N; / &#xN; conforme o que for mais curto)
+//          - em HTML, '&' só deixa de ser escapado se não formar uma referência
+//            ambígua com o que vem a seguir (ex: &amp;copy; fica como está)
+//          Referências desconhecidas, inválidas ou sem ';' ficam intactas.
+// License: MIT
+
+package minifier
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// minifyEntities reescreve as referências de s. quote é o contexto:
+// 0 = texto, '"' ou '\'' = valor de atributo com essas aspas,
+// ' ' = valor de atributo HTML sem aspas.
+func minifyEntities(s string, isHTML bool, quote byte) string {
+    if strings.IndexByte(s, '&') < 0 {
+        return s
+    }
+    out := make([]byte, 0, len(s))
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if c != '&' {
+            out = append(out, c)
+            continue
+        }
+        end := entityRefEnd(s, i)
+        if end < 0 {
+            out = append(out, c)
+            continue
+        }
+        ref := s[i : end+1]
+        i = end
+
+        decoded, ok := decodeEntityRef(ref, isHTML)
+        if !ok {
+            out = append(out, ref...)
+            continue
+        }
+        next := byte(0)
+        if end+1 < len(s) {
+            next = s[end+1]
+        }
+        if utf8.RuneCountInString(decoded) > 1 {
+            // entidades HTML com dois code points: só descodificar se nenhum
+            // precisar de escape
+            keep := false
+            for _, r := range decoded {
+                if entityNeedsEscape(r, out, next, isHTML, quote) {
+                    keep = true
+                }
+            }
+            if keep {
+                out = append(out, ref...)
+            } else {
+                out = append(out, decoded...)
+            }
+            continue
+        }
+        r, _ := utf8.DecodeRuneInString(decoded)
+        if entityNeedsEscape(r, out, next, isHTML, quote) {
+            out = append(out, shortestCharRef(r)...)
+        } else {
+            out = utf8.AppendRune(out, r)
+        }
+    }
+    return string(out)
+}
+
+// entityRefEnd devolve o índice do ';' que termina a referência em s[i] ('&'),
+// ou -1 se não for uma referência &nome; / &#...; completa.
+func entityRefEnd(s string, i int) int {
+    j := i + 1
+    for j < len(s) && j-i <= 40 && (isASCIIAlnum(s[j]) || (j == i+1 && s[j] == '#')) {
+        j++
+    }
+    if j == i+1 || j >= len(s) || s[j] != ';' {
+        return -1
+    }
+    return j
+}
+
+// decodeEntityRef interpreta uma referência completa (ex: "&eacute;").
+// Em XML só existem as cinco entidades pré-definidas e as referências numéricas.
+func decodeEntityRef(ref string, isHTML bool) (string, bool) {
+    name := ref[1 : len(ref)-1]
+    if !isHTML {
+        switch name {
+        case "lt":
+            return "<", true
+        case "gt":
+            return ">", true
+        case "amp":
+            return "&", true
+        case "apos":
+            return "'", true
+        case "quot":
+            return `"`, true
+        }
+        if !strings.HasPrefix(name, "#") {
+            return "", false // entidade do DOCTYPE: não sabemos o valor
+        }
+        r, ok := parseCharRef(name[1:])
+        if !ok {
+            return "", false
+        }
+        return string(r), true
+    }
+
+    u := html.UnescapeString(ref)
+    if u == ref || strings.ContainsRune(u, utf8.RuneError) {
+        return "", false
+    }
+    // html.UnescapeString aceita prefixos legados sem ';' (&notit; → ¬it;):
+    // só serve se a referência inteira foi consumida
+    if strings.HasSuffix(u, ";") && name != "semi" {
+        return "", false
+    }
+    return u, true
+}
+
+// entityNeedsEscape indica se o carácter r tem de continuar escapado quando
+// escrito a seguir a out (next é o byte seguinte no input).
+func entityNeedsEscape(r rune, out []byte, next byte, isHTML bool, quote byte) bool {
+    switch {
+    case r == '\r', r < 0x20 && r != '\t' && r != '\n' && !(isHTML && r == '\f'):
+        return true
+    case r >= 0x7F && r <= 0x9F, r == 0x2028, r == 0xFEFF:
+        // controlos/quebras de linha invisíveis ficam sempre visíveis como referência
+        return true
+    case r == '<':
+        return quote == 0 || quote == ' ' || !isHTML
+    case r == '&':
+        // em HTML "&" solto é texto, desde que não comece uma referência
+        return !isHTML || isASCIIAlnum(next) || next == '#'
+    case r == '>':
+        if quote == ' ' {
+            return true
+        }
+        // "]]>" não pode aparecer no texto XML
+        return !isHTML && quote == 0 && len(out) >= 2 && out[len(out)-1] == ']' && out[len(out)-2] == ']'
+    case r == ']' && !isHTML && quote == 0 && (next == ']' || next == '>'):
+        return true
+    }
+
+    if quote != 0 {
+        if byte(r) == quote && r < 0x80 {
+            return true
+        }
+        if quote == ' ' && strings.ContainsRune(" \t\n\f\"'=`", r) {
+            return true
+        }
+        if !isHTML && (r == '\t' || r == '\n') {
+            // seriam normalizados para espaço no valor do atributo
+            return true
+        }
+    }
+
+    // em HTML, não criar uma referência nova com um '&' literal anterior
+    // (ex: "&" seguido de &#97;mp; → "&amp;")
+    if isHTML && ((r < 0x80 && isASCIIAlnum(byte(r))) || r == '#' || r == ';') {
+        k := len(out)
+        for k > 0 && (isASCIIAlnum(out[k-1]) || out[k-1] == '#') {
+            k--
+        }
+        if k > 0 && out[k-1] == '&' {
+            return true
+        }
+    }
+    return false
+}
+
+// shortestCharRef devolve a forma mais curta de escrever r como referência.
+func shortestCharRef(r rune) string {
+    switch r {
+    case '<':
+        return "&lt;"
+    case '>':
+        return "&gt;"
+    case '&':
+        return "&amp;"
+    }
+    dec := "&#" + strconv.Itoa(int(r)) + ";"
+    hex := "&#x" + strings.ToUpper(strconv.FormatInt(int64(r), 16)) + ";"
+    if len(hex) < len(dec) {
+        return hex
+    }
+    return dec
+}
+
+func isASCIIAlnum(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// minifyHTMLEntities aplica minifyEntities ao texto e aos valores dos atributos
+// de um documento HTML, sem tocar em comentários, declarações e nomes.
+func minifyHTMLEntities(s string) string {
+    var out strings.Builder
+    textStart := 0
+    flushText := func(end int) {
+        out.WriteString(minifyEntities(s[textStart:end], true, 0))
+    }
+
+    for i := 0; i < len(s); {
+        if s[i] != '<' || i+1 >= len(s) {
+            i++
+            continue
+        }
+        var end int
+        switch {
+        case strings.HasPrefix(s[i:], "<!--"):
+            end = strings.Index(s[i+4:], "-->")
+            if end < 0 {
+                end = len(s)
+            } else {
+                end = i + 4 + end + 3
+            }
+        case s[i+1] == '!' || s[i+1] == '?':
+            end = scanDeclEnd(s, i+2)
+        case s[i+1] == '/' || isMarkupNameStart(s[i+1]):
+            end = scanTagEnd(s, i+1)
+        default:
+            i++
+            continue
+        }
+        flushText(i)
+        if s[i+1] == '/' || isMarkupNameStart(s[i+1]) {
+            out.WriteString(minifyTagEntities(s[i:end]))
+        } else {
+            out.WriteString(s[i:end])
+        }
+        i = end
+        textStart = end
+    }
+    flushText(len(s))
+    return out.String()
+}
+
+// minifyTagEntities reescreve os valores dos atributos de uma tag HTML.
+func minifyTagEntities(tag string) string {
+    if strings.IndexByte(tag, '&') < 0 {
+        return tag
+    }
+    var out strings.Builder
+    for i := 0; i < len(tag); i++ {
+        c := tag[i]
+        if c != '=' {
+            out.WriteByte(c)
+            continue
+        }
+        out.WriteByte(c)
+        j := i + 1
+        for j < len(tag) && isMarkupSpace(tag[j]) {
+            j++
+        }
+        out.WriteString(tag[i+1 : j])
+        if j >= len(tag) {
+            i = j - 1
+            continue
+        }
+        if q := tag[j]; q == '"' || q == '\'' {
+            k := strings.IndexByte(tag[j+1:], q)
+            if k < 0 {
+                out.WriteString(tag[j:])
+                break
+            }
+            out.WriteByte(q)
+            out.WriteString(minifyEntities(tag[j+1:j+1+k], true, q))
+            out.WriteByte(q)
+            i = j + 1 + k
+            continue
+        }
+        k := j
+        for k < len(tag) && !isMarkupSpace(tag[k]) && tag[k] != '>' {
+            k++
+        }
+        out.WriteString(minifyEntities(tag[j:k], true, ' '))
+        i = k - 1
+    }
+    return out.String()
+}
@@ -0,0 +1,68 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a minimização de referências a caracteres/entidades
+// License: MIT
+
+package minifier
+
+import "testing"
+
+func TestMinifyEntities(t *testing.T) {
+    tests := []struct {
+        name     string
+        input    string
+        isHTML   bool
+        quote    byte
+        expected string
+    }{
+        {"HTML texto", "caf&eacute; &#233; &#x20AC; &euro;", true, 0, "café é € €"},
+        {"HTML escapes necessários", "a &lt; b &#60; c &gt; d", true, 0, "a &lt; b &lt; c > d"},
+        {"HTML & ambíguo", "&amp;copy; &amp; &amp;#1; x&amp;", true, 0, "&amp;copy; & &amp;#1; x&"},
+        {"HTML não criar referência", "&&#97;mp;", true, 0, "&&#97;mp;"},
+        {"HTML entidade desconhecida/legada", "&foo; &notit; &copy", true, 0, "&foo; &notit; &copy"},
+        {"HTML C1 e CR", "&#128;&#13;&#x85;", true, 0, "€&#13;…"},
+        {"HTML entidade com dois code points", "&NotEqualTilde;", true, 0, "≂̸"},
+        {"HTML atributo com aspas", "&quot;a&quot; &#39;b&#39; &lt;", true, '"', "&#34;a&#34; 'b' <"},
+        {"HTML atributo com plicas", "&quot;a&quot; &apos;b&apos;", true, '\'', `"a" &#39;b&#39;`},
+        {"HTML atributo sem aspas", "a&#32;b&eacute;&#61;", true, ' ', "a&#32;bé&#61;"},
+        {"XML texto", "&#233;&amp;&lt;&gt;&quot;&apos;", false, 0, "é&amp;&lt;>\"'"},
+        {"XML ]]>", "]]&gt; ]&#93;> &#93;]>", false, 0, "]]&gt; ]&#93;> &#93;]>"},
+        {"XML atributo", "&#9;&#10;&#13;&#x22;&apos;&eacute;", false, '"', "&#9;&#10;&#13;&#34;'&eacute;"},
+        {"XML referência inválida", "&#0; &#xD800;", false, 0, "&#0; &#xD800;"},
+        {"Forma mais curta", "&#x0000D;&#000233;", false, '"', "&#13;é"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := minifyEntities(tt.input, tt.isHTML, tt.quote)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+        })
+    }
+}
+
+func TestMinifyHTMLEntities(t *testing.T) {
+    opts := DefaultOptions()
+    opts.MinifyHTMLEntities = true
+
+    input := `<p title="caf&eacute; &quot;x&quot;" data-v=a&amp;b>Ol&aacute; &amp; adeus</p>` +
+        `<!-- &eacute; --><script>var s = "&eacute;";</script>`
+    expected := `<p title="café &#34;x&#34;" data-v=a&amp;b>Olá & adeus</p><script>var s="&eacute;";</script>`
+    if got := MinifyHTML(input, opts); got != expected {
+        t.Errorf("got  %s\nwant %s", got, expected)
+    }
+}
+
+func TestMinifyXMLEntities(t *testing.T) {
+    opts := DefaultOptions()
+    opts.XMLMinifyEntities = true
+
+    input := `<r a="caf&#233; &#x22;" b='&apos;&quot;'>&#x20AC; &lt; &#62;<![CDATA[&#233;]]></r>`
+    expected := `<r a="café &#34;" b='&#39;"'>€ &lt; ><![CDATA[&#233;]]></r>`
+    got := MinifyXML(input, opts)
+    if got != expected {
+        t.Errorf("got  %s\nwant %s", got, expected)
+    }
+    assertSameXML(t, input, got)
+}
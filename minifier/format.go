@@ -0,0 +1,479 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: modo inverso da minificação (pretty-print) para ler ficheiros .min:
+//          Beautify por tipo e formatadores de JSON, CSS e JS.
+//          Cada formatador parte do resultado do minificador correspondente,
+//          por isso o output não depende da formatação original.
+// License: MIT
+
+package minifier
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// Beautify por tipo
+func Beautify(input string, t Type, opts *Options) (string, error) {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    switch t {
+    case HTML:
+        return BeautifyHTML(input, opts), nil
+    case CSS:
+        return BeautifyCSS(input, opts), nil
+    case JS:
+        return BeautifyJS(input, opts), nil
+    case JSON:
+        return BeautifyJSON(input, opts), nil
+    case XML, SVG:
+        return BeautifyXML(input, opts), nil
+    default:
+        return "", errors.New("tipo não suportado")
+    }
+}
+
+// BeautifyFile lê, deteta tipo e formata
+func BeautifyFile(path string, opts *Options) (string, error) {
+    b, err := os.ReadFile(path)
+    if err != nil { return "", err }
+    t := DetectType(path)
+    if t == ERROR { return "", errors.New("tipo não suportado") }
+    return Beautify(string(b), t, opts)
+}
+
+// prettyWriter acumula o output formatado; a indentação só é escrita quando
+// aparece conteúdo numa linha nova, por isso mudar depth entre newline() e
+// write() funciona como esperado.
+type prettyWriter struct {
+    buf         []byte
+    indent      string
+    depth       int
+    atLineStart bool
+}
+
+func newPrettyWriter(opts *Options) *prettyWriter {
+    indent := "\t"
+    if !opts.FormatUseTabs {
+        w := opts.FormatIndentWidth
+        if w < 0 {
+            w = 0
+        }
+        indent = strings.Repeat(" ", w)
+    }
+    return &prettyWriter{indent: indent, atLineStart: true}
+}
+
+func (p *prettyWriter) write(s string) {
+    if s == "" {
+        return
+    }
+    if p.atLineStart {
+        for i := 0; i < p.depth; i++ {
+            p.buf = append(p.buf, p.indent...)
+        }
+        p.atLineStart = false
+    }
+    p.buf = append(p.buf, s...)
+}
+
+func (p *prettyWriter) writeByte(c byte) {
+    if p.atLineStart {
+        p.write(string(c))
+        return
+    }
+    p.buf = append(p.buf, c)
+}
+
+// newline termina a linha atual (se tiver conteúdo), removendo espaços à direita.
+func (p *prettyWriter) newline() {
+    if p.atLineStart {
+        return
+    }
+    for len(p.buf) > 0 && (p.buf[len(p.buf)-1] == ' ' || p.buf[len(p.buf)-1] == '\t') {
+        p.buf = p.buf[:len(p.buf)-1]
+    }
+    p.buf = append(p.buf, '\n')
+    p.atLineStart = true
+}
+
+// writeLines escreve um bloco já formatado, indentando cada linha ao nível atual.
+func (p *prettyWriter) writeLines(s string) {
+    for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+        p.newline()
+        p.write(line)
+    }
+    p.newline()
+}
+
+func (p *prettyWriter) dedent() {
+    if p.depth > 0 {
+        p.depth--
+    }
+}
+
+// last devolve o último byte escrito (0 se ainda não há output).
+func (p *prettyWriter) last() byte {
+    if len(p.buf) == 0 {
+        return 0
+    }
+    return p.buf[len(p.buf)-1]
+}
+
+func (p *prettyWriter) String() string {
+    p.newline()
+    return strings.TrimRight(string(p.buf), "\n")
+}
+
+// BeautifyJSON formata JSON com um valor por linha.
+func BeautifyJSON(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    b := []byte(MinifyJSONWithOptions(input, opts))
+    p := newPrettyWriter(opts)
+
+    for i := 0; i < len(b); i++ {
+        c := b[i]
+
+        if c == '"' {
+            // copiar a string inteira de uma vez
+            j := i + 1
+            for j < len(b) && b[j] != '"' {
+                if b[j] == '\\' {
+                    j++
+                }
+                j++
+            }
+            if j >= len(b) {
+                j = len(b) - 1
+            }
+            p.write(string(b[i : j+1]))
+            i = j
+            continue
+        }
+
+        switch c {
+        case '{', '[':
+            // {} e [] vazios ficam na mesma linha
+            if i+1 < len(b) && (b[i+1] == '}' || b[i+1] == ']') {
+                p.write(string(b[i : i+2]))
+                i++
+                continue
+            }
+            p.writeByte(c)
+            p.depth++
+            p.newline()
+        case '}', ']':
+            p.dedent()
+            p.newline()
+            p.writeByte(c)
+        case ',':
+            p.writeByte(c)
+            p.newline()
+        case ':':
+            p.write(": ")
+        default:
+            p.writeByte(c)
+        }
+    }
+
+    return p.String()
+}
+
+// BeautifyCSS formata CSS com uma declaração por linha e blocos indentados.
+func BeautifyCSS(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    b := []byte(MinifyCSS(input))
+    p := newPrettyWriter(opts)
+
+    parens := 0
+    stmtStart := true
+    isDecl := false   // a instrução atual é "propriedade: valor"
+    colonDone := false
+
+    for i := 0; i < len(b); i++ {
+        c := b[i]
+
+        // comentários /*! ... */ preservados pelo minificador
+        if c == '/' && i+1 < len(b) && b[i+1] == '*' {
+            end := strings.Index(string(b[i+2:]), "*/")
+            if end < 0 {
+                end = len(b)
+            } else {
+                end = i + 2 + end + 2
+            }
+            p.newline()
+            p.write(string(b[i:end]))
+            p.newline()
+            i = end - 1
+            stmtStart = true
+            continue
+        }
+
+        if stmtStart && c != ' ' {
+            stmtStart = false
+            isDecl = p.depth > 0 && cssStatementEnd(b, i) != '{'
+            colonDone = false
+        }
+
+        // strings
+        if c == '"' || c == '\'' {
+            j := i + 1
+            for j < len(b) && b[j] != c {
+                if b[j] == '\\' {
+                    j++
+                }
+                j++
+            }
+            if j >= len(b) {
+                j = len(b) - 1
+            }
+            p.write(string(b[i : j+1]))
+            i = j
+            continue
+        }
+
+        switch c {
+        case '(':
+            parens++
+            p.writeByte(c)
+            continue
+        case ')':
+            if parens > 0 {
+                parens--
+            }
+            p.writeByte(c)
+            continue
+        }
+        if parens > 0 {
+            p.writeByte(c)
+            continue
+        }
+
+        switch c {
+        case '{':
+            if p.last() != ' ' {
+                p.writeByte(' ')
+            }
+            p.writeByte('{')
+            p.depth++
+            p.newline()
+            stmtStart = true
+        case '}':
+            p.dedent()
+            p.newline()
+            p.writeByte('}')
+            p.newline()
+            stmtStart = true
+        case ';':
+            p.writeByte(';')
+            p.newline()
+            stmtStart = true
+        case ':':
+            p.writeByte(':')
+            if isDecl && !colonDone {
+                p.writeByte(' ')
+                colonDone = true
+            }
+        case ',':
+            p.write(", ")
+        default:
+            if c == ' ' && p.atLineStart {
+                continue
+            }
+            p.writeByte(c)
+        }
+    }
+
+    return p.String()
+}
+
+// cssStatementEnd devolve o primeiro '{', ';' ou '}' a partir de i, fora de
+// strings e parênteses (0 se não existir).
+func cssStatementEnd(b []byte, i int) byte {
+    parens := 0
+    quote := byte(0)
+    for ; i < len(b); i++ {
+        c := b[i]
+        if quote != 0 {
+            if c == '\\' {
+                i++
+            } else if c == quote {
+                quote = 0
+            }
+            continue
+        }
+        switch c {
+        case '"', '\'':
+            quote = c
+        case '(':
+            parens++
+        case ')':
+            if parens > 0 {
+                parens--
+            }
+        case '{', ';', '}':
+            if parens == 0 {
+                return c
+            }
+        }
+    }
+    return 0
+}
+
+// BeautifyJS formata JS quebrando linhas em '{', '}' e ';' (fora de parênteses)
+// e indentando blocos. Pensado para ler código minificado: o input passa
+// primeiro por MinifyJS, que não insere ';' em todas as quebras de linha.
+func BeautifyJS(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    b := []byte(MinifyJS(input))
+    p := newPrettyWriter(opts)
+
+    var stack []byte // '(', '[' ou '{' abertos
+    var wordBuf []byte
+    lastWord := ""
+    prevNonSpace := byte(0)
+
+    // copyLiteral copia string/template/regex a partir de i e devolve o índice final
+    copyLiteral := func(i int, regex bool) int {
+        quote := b[i]
+        inClass := false
+        j := i + 1
+        for j < len(b) {
+            c := b[j]
+            if c == '\\' {
+                j += 2
+                continue
+            }
+            if regex {
+                if c == '[' {
+                    inClass = true
+                } else if c == ']' {
+                    inClass = false
+                } else if c == '/' && !inClass {
+                    break
+                }
+            } else if c == quote {
+                break
+            }
+            j++
+        }
+        if j >= len(b) {
+            j = len(b) - 1
+        }
+        p.write(string(b[i : j+1]))
+        return j
+    }
+
+    for i := 0; i < len(b); i++ {
+        c := b[i]
+
+        if isIdentChar(c) {
+            wordBuf = append(wordBuf, c)
+            p.writeByte(c)
+            prevNonSpace = c
+            continue
+        }
+        if len(wordBuf) > 0 {
+            lastWord = string(wordBuf)
+            wordBuf = wordBuf[:0]
+        }
+
+        switch c {
+        case '"', '\'', '`':
+            i = copyLiteral(i, false)
+            prevNonSpace = c
+            lastWord = ""
+            continue
+        case '/':
+            if isRegexStart(prevNonSpace, lastWord) {
+                i = copyLiteral(i, true)
+                prevNonSpace = '/'
+                lastWord = ""
+                continue
+            }
+        }
+
+        top := byte(0)
+        if len(stack) > 0 {
+            top = stack[len(stack)-1]
+        }
+
+        switch c {
+        case '(', '[':
+            stack = append(stack, c)
+            p.writeByte(c)
+        case ')', ']':
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+            p.writeByte(c)
+        case '{':
+            if i+1 < len(b) && b[i+1] == '}' {
+                p.write("{}")
+                i++
+                prevNonSpace = '}'
+                continue
+            }
+            if l := p.last(); l == ')' || isIdentChar(l) {
+                p.writeByte(' ')
+            }
+            p.writeByte('{')
+            stack = append(stack, '{')
+            p.depth++
+            p.newline()
+        case '}':
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+            p.dedent()
+            p.newline()
+            p.writeByte('}')
+            next := jsNextWord(b, i+1)
+            switch {
+            case next == "else" || next == "catch" || next == "finally":
+                p.writeByte(' ')
+            case i+1 < len(b) && strings.IndexByte(");,.]", b[i+1]) >= 0:
+                // continua a expressão na mesma linha
+            default:
+                p.newline()
+            }
+        case ';':
+            p.writeByte(';')
+            if top == '(' {
+                p.writeByte(' ')
+            } else {
+                p.newline()
+            }
+        case ',':
+            p.write(", ")
+        case ' ':
+            if !p.atLineStart {
+                p.writeByte(' ')
+            }
+        default:
+            p.writeByte(c)
+        }
+        if c != ' ' {
+            prevNonSpace = c
+        }
+    }
+
+    return p.String()
+}
+
+// jsNextWord devolve o identificador que começa em i (ou "").
+func jsNextWord(b []byte, i int) string {
+    j := i
+    for j < len(b) && isIdentChar(b[j]) {
+        j++
+    }
+    return string(b[i:j])
+}
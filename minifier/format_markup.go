@@ -0,0 +1,304 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: pretty-print de XML e HTML a partir dos tokens de markup:
+//          um elemento por linha, indentado pela profundidade; elementos que só
+//          contêm texto ficam numa linha; em HTML, elementos inline e texto
+//          seguem no mesmo fluxo e <script>/<style> são formatados como JS/CSS.
+// License: MIT
+
+package minifier
+
+import "strings"
+
+// BeautifyXML formata XML com um elemento por linha. Como em MinifyXML, o
+// conteúdo com xml:space="preserve" ou de opts.XMLPreserveWhitespaceElements
+// sai tal como está; elementos com conteúdo misto (<p>a <b>b</b> c</p>) ficam
+// numa só linha, sem perder os espaços entre o texto e os filhos.
+func BeautifyXML(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    toks := tokenizeMarkup(input, false)
+    p := newPrettyWriter(opts)
+
+    for i := 0; i < len(toks); i++ {
+        t := toks[i]
+        switch t.kind {
+        case markupText:
+            s := strings.Trim(t.raw, " \t\r\n")
+            if s == "" {
+                continue
+            }
+            p.newline()
+            p.write(s)
+        case markupStart:
+            p.newline()
+            end := matchingEnd(toks, i)
+            if xmlPreservesSpace(t, opts) {
+                // o whitespace dos descendentes também conta (é herdado)
+                last := toks[end]
+                p.write(input[t.pos : last.pos+len(last.raw)])
+                i = end
+                continue
+            }
+            if xmlMixedContent(toks, i, end) {
+                p.write(renderXMLInline(toks, i, end))
+                i = end
+                continue
+            }
+            p.write(renderMarkupTag(t))
+
+            // <a></a> e <a>texto</a> ficam numa só linha
+            if i+1 < len(toks) && toks[i+1].kind == markupEnd {
+                p.write("</" + toks[i+1].name + ">")
+                i++
+                continue
+            }
+            if i+2 < len(toks) && toks[i+1].kind == markupText && toks[i+2].kind == markupEnd {
+                p.write(strings.Trim(toks[i+1].raw, " \t\r\n"))
+                p.write("</" + toks[i+2].name + ">")
+                i += 2
+                continue
+            }
+            p.depth++
+        case markupEnd:
+            p.dedent()
+            p.newline()
+            p.write("</" + t.name + ">")
+        case markupEmpty:
+            p.newline()
+            p.write(renderMarkupTag(t))
+        default:
+            // comentários, CDATA, PI e declarações ficam tal como estão
+            p.newline()
+            p.write(t.raw)
+        }
+    }
+
+    return p.String()
+}
+
+// xmlPreservesSpace diz se o elemento aberto em t começa um bloco com
+// whitespace significativo (o resto da pilha de MinifyXML não é preciso aqui:
+// os descendentes de um bloco preservado são escritos com ele).
+func xmlPreservesSpace(t markupToken, opts *Options) bool {
+    for _, a := range t.attrs {
+        if a.name == "xml:space" {
+            return a.value == "preserve"
+        }
+    }
+    return isXMLPreserveElement(t.name, opts.XMLPreserveWhitespaceElements)
+}
+
+// xmlMixedContent diz se o elemento toks[i..end] tem, como filhos diretos,
+// texto (não só whitespace) e elementos.
+func xmlMixedContent(toks []markupToken, i, end int) bool {
+    hasText, hasElem := false, false
+    depth := 0
+    for k := i + 1; k < end; k++ {
+        switch toks[k].kind {
+        case markupStart:
+            if depth == 0 {
+                hasElem = true
+            }
+            depth++
+        case markupEnd:
+            depth--
+        case markupEmpty:
+            if depth == 0 {
+                hasElem = true
+            }
+        case markupText:
+            if depth == 0 && !isAllXMLWhitespace(toks[k].raw) {
+                hasText = true
+            }
+        }
+    }
+    return hasText && hasElem
+}
+
+// renderXMLInline escreve toks[i..end] numa linha: cada sequência de whitespace
+// passa a um espaço, e o whitespace logo a seguir à abertura e antes do fecho sai.
+func renderXMLInline(toks []markupToken, i, end int) string {
+    var b strings.Builder
+    for k := i; k <= end; k++ {
+        t := toks[k]
+        switch t.kind {
+        case markupStart, markupEmpty:
+            b.WriteString(renderMarkupTag(t))
+        case markupEnd:
+            b.WriteString("</" + t.name + ">")
+        case markupText:
+            s := collapseHTMLSpaces(t.raw)
+            if k == i+1 {
+                s = strings.TrimLeft(s, " ")
+            }
+            if k == end-1 {
+                s = strings.TrimRight(s, " ")
+            }
+            b.WriteString(s)
+        default:
+            b.WriteString(t.raw)
+        }
+    }
+    return b.String()
+}
+
+// BeautifyHTML formata HTML: elementos de bloco em linhas próprias, conteúdo
+// inline no mesmo fluxo, <pre>/<textarea>/<title> intactos e <script>/<style>
+// formatados com BeautifyJS/BeautifyCSS (ou BeautifyJSON para JSON).
+func BeautifyHTML(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    toks := tokenizeMarkup(input, true)
+    p := newPrettyWriter(opts)
+
+    // atributo type do último <script> aberto (para distinguir JSON de JS)
+    scriptType := ""
+
+    for i := 0; i < len(toks); i++ {
+        t := toks[i]
+        switch t.kind {
+        case markupText:
+            s := collapseHTMLSpaces(t.raw)
+            if strings.TrimSpace(s) == "" {
+                // whitespace só conta entre conteúdo inline
+                if !p.atLineStart && i+1 < len(toks) && htmlTokenIsInline(toks[i+1]) {
+                    p.writeByte(' ')
+                }
+                continue
+            }
+            if p.atLineStart {
+                s = strings.TrimLeft(s, " ")
+            }
+            p.write(s)
+
+        case markupStart, markupEmpty:
+            if isInlineTag(t.name) {
+                p.write(renderMarkupTag(t))
+                continue
+            }
+            p.newline()
+            p.write(renderMarkupTag(t))
+            if t.kind == markupEmpty || isHTMLVoidElement(t.name) {
+                p.newline()
+                continue
+            }
+            if t.name == "script" {
+                scriptType = ""
+                for _, a := range t.attrs {
+                    if strings.EqualFold(a.name, "type") {
+                        scriptType = strings.ToLower(strings.TrimSpace(a.value))
+                    }
+                }
+            }
+            if i+1 < len(toks) && toks[i+1].kind == markupEnd && toks[i+1].name == t.name {
+                // elemento vazio (<script src="..."></script>) numa só linha
+                p.write("</" + t.name + ">")
+                p.newline()
+                i++
+                continue
+            }
+            if isHTMLVerbatimElement(t.name) {
+                continue
+            }
+            p.depth++
+            p.newline()
+
+        case markupRawText:
+            switch t.name {
+            case "script", "style":
+                var s string
+                switch {
+                case t.name == "style":
+                    s = BeautifyCSS(t.raw, opts)
+                case scriptType == "application/json" || scriptType == "application/ld+json":
+                    s = BeautifyJSON(t.raw, opts)
+                case scriptType == "" || strings.Contains(scriptType, "javascript") || scriptType == "module":
+                    s = BeautifyJS(t.raw, opts)
+                default:
+                    // templates e outros tipos: não sabemos formatar
+                    s = strings.TrimSpace(t.raw)
+                }
+                if s != "" {
+                    p.writeLines(s)
+                }
+            default:
+                // pre/textarea/title: conteúdo intacto, sem quebras novas
+                p.write(t.raw)
+            }
+
+        case markupEnd:
+            if isInlineTag(t.name) {
+                p.write(t.raw)
+                continue
+            }
+            if !isHTMLVerbatimElement(t.name) {
+                p.dedent()
+                p.newline()
+            }
+            p.write("</" + t.name + ">")
+            p.newline()
+
+        default:
+            p.newline()
+            p.write(t.raw)
+            p.newline()
+        }
+    }
+
+    return p.String()
+}
+
+// htmlTokenIsInline indica se o token pode continuar uma linha de texto.
+func htmlTokenIsInline(t markupToken) bool {
+    switch t.kind {
+    case markupText:
+        return strings.TrimSpace(t.raw) != ""
+    case markupStart, markupEmpty, markupEnd:
+        return isInlineTag(t.name)
+    default:
+        return false
+    }
+}
+
+// elementos HTML sem tag de fecho
+func isHTMLVoidElement(name string) bool {
+    switch name {
+    case "area", "base", "br", "col", "embed", "hr", "img", "input",
+        "link", "meta", "param", "source", "track", "wbr":
+        return true
+    default:
+        return false
+    }
+}
+
+// elementos cujo conteúdo é escrito sem mudar quebras de linha/indentação
+func isHTMLVerbatimElement(name string) bool {
+    switch name {
+    case "pre", "textarea", "title":
+        return true
+    default:
+        return false
+    }
+}
+
+// collapseHTMLSpaces troca cada sequência de whitespace por um único espaço.
+func collapseHTMLSpaces(s string) string {
+    var b strings.Builder
+    inSpace := false
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+            if !inSpace {
+                b.WriteByte(' ')
+            }
+            inSpace = true
+            continue
+        }
+        inSpace = false
+        b.WriteByte(c)
+    }
+    return b.String()
+}
@@ -0,0 +1,59 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a formatação (pretty-print) de XML e HTML
+// License: MIT
+
+package minifier
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBeautifyXML(t *testing.T) {
+    in := `<?xml version="1.0"?><root  a="1"><c>text</c><d/><!-- x --><f><g>y</g></f></root>`
+    expected := "<?xml version=\"1.0\"?>\n<root a=\"1\">\n  <c>text</c>\n  <d/>\n  <!-- x -->\n  <f>\n    <g>y</g>\n  </f>\n</root>"
+    got := BeautifyXML(in, nil)
+    if got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+    if MinifyXML(got, nil) != MinifyXML(in, nil) {
+        t.Errorf("round-trip falhou: %q", MinifyXML(got, nil))
+    }
+}
+
+func TestBeautifyXMLWhitespace(t *testing.T) {
+    in := `<r><pre xml:space="preserve">  a
+   <b> b </b>
+</pre><p>a <b>b</b> c</p><q>  x  </q></r>`
+    expected := "<r>\n  <pre xml:space=\"preserve\">  a\n   <b> b </b>\n</pre>\n  <p>a <b>b</b> c</p>\n  <q>x</q>\n</r>"
+    got := BeautifyXML(in, nil)
+    if got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+
+    // elementos da lista de opts.XMLPreserveWhitespaceElements
+    opts := DefaultOptions()
+    opts.XMLPreserveWhitespaceElements = []string{"code"}
+    in = "<r><code>  if x {\n    y\n  }</code></r>"
+    if got := BeautifyXML(in, opts); got != "<r>\n  <code>  if x {\n    y\n  }</code>\n</r>" {
+        t.Errorf("lista: got %q", got)
+    }
+}
+
+func TestBeautifyHTML(t *testing.T) {
+    in := `<html><head><style>a{color:red}</style><script src="x.js"></script></head>` +
+        `<body><div><p>Olá <b>mundo</b>!</p><br><pre>  a
+  b</pre></div></body></html>`
+    got := BeautifyHTML(in, nil)
+    for _, want := range []string{
+        "<html>\n  <head>\n    <style>\n      a {\n        color: red\n      }\n    </style>\n",
+        "    <script src=\"x.js\"></script>\n",
+        "      <p>\n        Olá <b>mundo</b>!\n      </p>\n      <br>\n",
+        "<pre>  a\n  b</pre>",
+    } {
+        if !strings.Contains(got, want) {
+            t.Errorf("falta %q em:\n%s", want, got)
+        }
+    }
+}
@@ -0,0 +1,69 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a formatação (pretty-print) de JSON, CSS e JS
+// License: MIT
+
+package minifier
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBeautifyJSON(t *testing.T) {
+    in := `{"a":[1,2],"b":{},"c":"x,{y}"}`
+    expected := "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {},\n  \"c\": \"x,{y}\"\n}"
+    got := BeautifyJSON(in, nil)
+    if got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+    // o resultado volta a minificar para o original
+    if MinifyJSON(got) != in {
+        t.Errorf("round-trip falhou: %q", MinifyJSON(got))
+    }
+}
+
+func TestBeautifyIndentOptions(t *testing.T) {
+    opts := DefaultOptions()
+    opts.FormatUseTabs = true
+    if got := BeautifyJSON(`{"a":1}`, opts); got != "{\n\t\"a\": 1\n}" {
+        t.Errorf("tabs: got %q", got)
+    }
+    opts.FormatUseTabs = false
+    opts.FormatIndentWidth = 4
+    if got := BeautifyJSON(`{"a":1}`, opts); got != "{\n    \"a\": 1\n}" {
+        t.Errorf("width 4: got %q", got)
+    }
+}
+
+func TestBeautifyCSS(t *testing.T) {
+    in := `@media print{a:hover,b{color:red;background:url(data:x;y)}}p{margin:0}`
+    expected := "@media print {\n  a:hover, b {\n    color: red;\n    background: url(data:x;y)\n  }\n}\np {\n  margin: 0\n}"
+    got := BeautifyCSS(in, nil)
+    if got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+}
+
+func TestBeautifyJS(t *testing.T) {
+    in := `function f(a){if(a){return "x;{"}else{for(var i=0;i<3;i++){g(i)}}}`
+    got := BeautifyJS(in, nil)
+    for _, want := range []string{
+        "function f(a) {\n",
+        "\n  if(a) {\n    return \"x;{\"\n  } else {\n",
+        "for(var i=0; i<3; i++) {\n      g(i)\n    }",
+    } {
+        if !strings.Contains(got, want) {
+            t.Errorf("falta %q em:\n%s", want, got)
+        }
+    }
+    if MinifyJS(got) != MinifyJS(in) {
+        t.Errorf("round-trip falhou: %q", MinifyJS(got))
+    }
+}
+
+func TestBeautifyUnsupportedType(t *testing.T) {
+    if _, err := Beautify("x", ERROR, nil); err == nil {
+        t.Error("esperado erro para tipo não suportado")
+    }
+}
@@ -1,8 +1,8 @@
 // Author: João Pinto
 // Date: 2025-12-15
 // Purpose: MinifyHTML minifica HTML preservando blocos sensíveis,
-//          minificando JS, CSS e JSON, e usando um minificador de whitespace
-//          com noção de contexto.
+//          minificando JS, CSS, JSON e SVG embebidos (através de opts.Registry),
+//          e usando um minificador de whitespace com noção de contexto.
 // License: MIT
 
 package minifier
@@ -123,6 +123,7 @@ func MinifyHTML(input string, opts *Options) string {
     })
 
     // 8) <style>...</style> → opcionalmente minificar CSS interno + placeholder
+    //    (o conteúdo embebido passa pelo minificador registado para o MIME type)
     reStyle := regexp.MustCompile(`(?is)(<style[^>]*>)(.*?)(</style>)`)
     html = reStyle.ReplaceAllStringFunc(html, func(m string) string {
         parts := reStyle.FindStringSubmatch(m)
@@ -131,12 +132,24 @@ func MinifyHTML(input string, opts *Options) string {
         }
         inner := parts[2]
         if opts.MinifyInlineCSS {
-            inner = MinifyCSS(inner)
+            inner = minifyEmbedded("text/css", inner, opts)
         }
         block := parts[1] + inner + parts[3]
         return addPlaceholder(block)
     })
 
+    // 8.1) <svg>...</svg> → opcionalmente otimizar com MinifySVG + placeholder
+    //      (cada <svg> é otimizado sozinho: os ids e os <defs> ficam, porque
+    //      podem ser usados por outro <svg> da página, ex: sprites com <use>)
+    if opts.MinifyInlineSVG {
+        svgOpts := *opts
+        svgOpts.SVGRemoveUnusedIDs = false
+        svgOpts.SVGRemoveUnusedDefs = false
+        html = replaceInlineSVG(html, func(m string) string {
+            return addPlaceholder(minifyEmbedded("image/svg+xml", m, &svgOpts))
+        })
+    }
+
     // 9) <script>...</script> → JS ou JSON interno + placeholder
     reScript := regexp.MustCompile(`(?is)(<script[^>]*>)(.*?)(</script>)`)
     html = reScript.ReplaceAllStringFunc(html, func(m string) string {
@@ -155,12 +168,12 @@ func MinifyHTML(input string, opts *Options) string {
             strings.Contains(openTag, `type='application/json'`) {
 
             if opts.MinifyJSONScripts {
-                inner = MinifyJSON(inner)
+                inner = minifyEmbedded("application/json", inner, opts)
             }
         } else {
             // JS normal
             if opts.MinifyInlineJS {
-                inner = MinifyJS(inner)
+                inner = minifyEmbedded("text/javascript", inner, opts)
             }
         }
 
@@ -169,7 +182,8 @@ func MinifyHTML(input string, opts *Options) string {
     })
 
     // 10) Minificar JSON em atributos data-json="..." / data-json='...'
-    //     usando MinifyJSON (se ativo).
+    //     usando MinifyJSON (se ativo). Aqui não se normalizam escapes: um
+    //     \u0022 descodificado partiria o valor do atributo.
     if opts.MinifyDataJSON {
         reDataJSONDouble := regexp.MustCompile(`(?is)(data-json\s*=\s*")([^"]*)(")`)
         html = reDataJSONDouble.ReplaceAllStringFunc(html, func(m string) string {
@@ -192,6 +206,12 @@ func MinifyHTML(input string, opts *Options) string {
         })
     }
 
+    // 10.1) Referências a caracteres/entidades no texto e nos atributos
+    //       (os blocos protegidos já estão em placeholders)
+    if opts.MinifyHTMLEntities {
+        html = minifyHTMLEntities(html)
+    }
+
     // 11) Minificar whitespace "por fora" (tags + texto)
     if opts.CollapseHTMLWhitespace {
         html = minifyHTMLWhitespace(html)
@@ -215,6 +235,47 @@ func MinifyHTML(input string, opts *Options) string {
     return html
 }
 
+// replaceInlineSVG substitui cada <svg> de topo (com os <svg> aninhados lá
+// dentro) por fn(bloco). Um <svg> sem fecho fica como está.
+func replaceInlineSVG(html string, fn func(string) string) string {
+    var b strings.Builder
+    last, start, depth := 0, 0, 0
+    for _, t := range tokenizeMarkup(html, true) {
+        if t.name != "svg" {
+            continue
+        }
+        switch t.kind {
+        case markupStart:
+            if depth == 0 {
+                start = t.pos
+            }
+            depth++
+        case markupEmpty:
+            if depth == 0 {
+                b.WriteString(html[last:t.pos])
+                b.WriteString(fn(t.raw))
+                last = t.pos + len(t.raw)
+            }
+        case markupEnd:
+            if depth == 0 {
+                continue
+            }
+            depth--
+            if depth == 0 {
+                end := t.pos + len(t.raw)
+                b.WriteString(html[last:start])
+                b.WriteString(fn(html[start:end]))
+                last = end
+            }
+        }
+    }
+    if last == 0 {
+        return html
+    }
+    b.WriteString(html[last:])
+    return b.String()
+}
+
 // minifyHTMLWhitespace colapsa whitespace de forma mais inteligente:
 //
 // - Dentro de tags (<...>):
@@ -1,23 +1,60 @@
 // Author: João Pinto
 // Date: 2025-12-15
-// Purpose: MinifyJSON remove todo o whitespace fora de strings. 
+// Purpose: MinifyJSON remove todo o whitespace fora de strings.
 //          Assume JSON válido e não altera nada dentro de strings.
+//          MinifyJSONWithOptions permite, opcionalmente, normalizar os escapes
+//          das strings (forma mais curta ou apenas ASCII).
 // License: MIT
 
 package minifier
 
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
 func MinifyJSON(input string) string {
-    var out []byte
+    return MinifyJSONWithOptions(input, nil)
+}
+
+// MinifyJSONWithOptions é igual a MinifyJSON, mas respeita as opções JSON*:
+//   - JSONNormalizeEscapes: reescreve cada string na forma mais curta válida
+//   - JSONASCIIOnly: escapa todo o não-ASCII como \uXXXX
+func MinifyJSONWithOptions(input string, opts *Options) string {
+    var out strings.Builder
+    MinifyJSONStream(&out, strings.NewReader(input), opts)
+    return out.String()
+}
+
+// minifyJSONStream é o minificador de JSON propriamente dito (ver stream.go).
+func minifyJSONStream(s *stream, opts *Options) error {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    rewrite := opts.JSONNormalizeEscapes || opts.JSONASCIIOnly
+
     inString := false
     escaped := false
+    str := s.scratch[:0] // conteúdo da string atual, quando é para reescrever
+    defer func() { s.scratch = str[:0] }()
 
-    b := []byte(input)
-
-    for i := 0; i < len(b); i++ {
-        c := b[i]
+    for {
+        c, err := s.readByte()
+        if err != nil {
+            if inString && rewrite {
+                // string não terminada: fica tal como está
+                s.write(str)
+            }
+            return err
+        }
 
         if inString {
-            out = append(out, c)
+            if rewrite {
+                str = append(str, c)
+            } else {
+                s.writeByte(c)
+            }
 
             if escaped {
                 // este char está escapado, voltamos ao normal
@@ -34,6 +71,15 @@ func MinifyJSON(input string) string {
             if c == '"' {
                 // fim da string
                 inString = false
+                if rewrite {
+                    raw := str[:len(str)-1]
+                    if r, ok := rewriteJSONString(raw, opts.JSONASCIIOnly); ok {
+                        raw = r
+                    }
+                    s.write(raw)
+                    s.writeByte('"')
+                    str = str[:0]
+                }
             }
 
             continue
@@ -47,11 +93,164 @@ func MinifyJSON(input string) string {
         case '"':
             inString = true
             escaped = false
-            out = append(out, c)
+            s.writeByte(c)
         default:
-            out = append(out, c)
+            s.writeByte(c)
         }
     }
+}
 
-    return string(out)
+// rewriteJSONString descodifica o conteúdo de uma string JSON (sem aspas) e
+// volta a codificá-lo com o mínimo de escapes. Em modo asciiOnly todo o
+// não-ASCII sai como \uXXXX (pares surrogate acima de U+FFFF).
+// Devolve ok=false se encontrar um escape inválido; nesse caso o chamador
+// mantém a string original.
+func rewriteJSONString(raw []byte, asciiOnly bool) ([]byte, bool) {
+    out := make([]byte, 0, len(raw))
+    prev := rune(-1) // último caractere escrito (para proteger "</")
+
+    for i := 0; i < len(raw); {
+        c := raw[i]
+
+        if c != '\\' {
+            r, size := utf8.DecodeRune(raw[i:])
+            if r == utf8.RuneError && size <= 1 {
+                // byte inválido em UTF-8: copiar tal como está
+                out = append(out, c)
+                prev = -1
+                i++
+                continue
+            }
+            out = appendJSONRune(out, r, prev, asciiOnly)
+            prev = r
+            i += size
+            continue
+        }
+
+        if i+1 >= len(raw) {
+            return nil, false
+        }
+        esc := raw[i+1]
+        i += 2
+
+        var r rune
+        switch esc {
+        case '"':
+            r = '"'
+        case '\\':
+            r = '\\'
+        case '/':
+            r = '/'
+        case 'b':
+            r = '\b'
+        case 'f':
+            r = '\f'
+        case 'n':
+            r = '\n'
+        case 'r':
+            r = '\r'
+        case 't':
+            r = '\t'
+        case 'u':
+            u, ok := parseHex4(raw, i)
+            if !ok {
+                return nil, false
+            }
+            i += 4
+            r = rune(u)
+            if utf16.IsSurrogate(r) {
+                // tentar juntar com o segundo elemento do par
+                if r < 0xDC00 && i+6 <= len(raw) && raw[i] == '\\' && raw[i+1] == 'u' {
+                    if lo, ok := parseHex4(raw, i+2); ok {
+                        if dec := utf16.DecodeRune(r, rune(lo)); dec != utf8.RuneError {
+                            r = dec
+                            i += 6
+                        }
+                    }
+                }
+                if utf16.IsSurrogate(r) {
+                    // surrogate isolado: não tem representação UTF-8, fica escapado
+                    out = appendJSONUnicodeEscape(out, r)
+                    prev = -1
+                    continue
+                }
+            }
+        default:
+            return nil, false
+        }
+
+        out = appendJSONRune(out, r, prev, asciiOnly)
+        prev = r
+    }
+
+    return out, true
+}
+
+// appendJSONRune escreve um caractere dentro de uma string JSON usando a
+// representação mais curta permitida.
+func appendJSONRune(out []byte, r rune, prev rune, asciiOnly bool) []byte {
+    switch r {
+    case '"':
+        return append(out, '\\', '"')
+    case '\\':
+        return append(out, '\\', '\\')
+    case '/':
+        // "</" fica como "<\/" para o JSON continuar seguro dentro de <script>
+        if prev == '<' {
+            return append(out, '\\', '/')
+        }
+        return append(out, '/')
+    case '\b':
+        return append(out, '\\', 'b')
+    case '\f':
+        return append(out, '\\', 'f')
+    case '\n':
+        return append(out, '\\', 'n')
+    case '\r':
+        return append(out, '\\', 'r')
+    case '\t':
+        return append(out, '\\', 't')
+    }
+
+    if r < 0x20 {
+        return appendJSONUnicodeEscape(out, r)
+    }
+    if r < utf8.RuneSelf || !asciiOnly {
+        return utf8.AppendRune(out, r)
+    }
+    if r > 0xFFFF {
+        hi, lo := utf16.EncodeRune(r)
+        out = appendJSONUnicodeEscape(out, hi)
+        return appendJSONUnicodeEscape(out, lo)
+    }
+    return appendJSONUnicodeEscape(out, r)
+}
+
+// appendJSONUnicodeEscape escreve \uXXXX (hex minúsculo) para um valor até U+FFFF.
+func appendJSONUnicodeEscape(out []byte, r rune) []byte {
+    const hex = "0123456789abcdef"
+    return append(out, '\\', 'u',
+        hex[(r>>12)&0xF], hex[(r>>8)&0xF], hex[(r>>4)&0xF], hex[r&0xF])
+}
+
+// parseHex4 lê 4 dígitos hexadecimais a partir de b[i].
+func parseHex4(b []byte, i int) (uint16, bool) {
+    if i+4 > len(b) {
+        return 0, false
+    }
+    var v uint16
+    for _, c := range b[i : i+4] {
+        v <<= 4
+        switch {
+        case c >= '0' && c <= '9':
+            v |= uint16(c - '0')
+        case c >= 'a' && c <= 'f':
+            v |= uint16(c - 'a' + 10)
+        case c >= 'A' && c <= 'F':
+            v |= uint16(c - 'A' + 10)
+        default:
+            return 0, false
+        }
+    }
+    return v, true
 }
@@ -42,3 +42,68 @@ func TestMinifyJSON(t *testing.T) {
         })
     }
 }
+
+func TestMinifyJSONEscapes(t *testing.T) {
+    tests := []struct {
+        name      string
+        input     string
+        normalize bool
+        ascii     bool
+        expected  string
+    }{
+        {
+            name:      "Decode unicode and slash",
+            input:     `{"a": "caf\u00e9 \/ x"}`,
+            normalize: true,
+            expected:  `{"a":"café / x"}`,
+        },
+        {
+            name:      "Keep required escapes",
+            input:     `{"a": "\"\\\n\u0001"}`,
+            normalize: true,
+            expected:  `{"a":"\"\\\n\u0001"}`,
+        },
+        {
+            name:      "Surrogate pair decoded",
+            input:     `["\ud83d\ude00"]`,
+            normalize: true,
+            expected:  `["😀"]`,
+        },
+        {
+            name:      "Lone surrogate kept",
+            input:     `["\uD800x"]`,
+            normalize: true,
+            expected:  `["\ud800x"]`,
+        },
+        {
+            name:      "Keep script-safe slash",
+            input:     `["<\/script>"]`,
+            normalize: true,
+            expected:  `["<\/script>"]`,
+        },
+        {
+            name:     "ASCII only",
+            input:    `{"nome": "João 😀"}`,
+            ascii:    true,
+            expected: `{"nome":"Jo\u00e3o \ud83d\ude00"}`,
+        },
+        {
+            name:      "Invalid escape left untouched",
+            input:     `["a\qb"]`,
+            normalize: true,
+            expected:  `["a\qb"]`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.JSONNormalizeEscapes = tt.normalize
+            opts.JSONASCIIOnly = tt.ascii
+            got := MinifyJSONWithOptions(tt.input, opts)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+        })
+    }
+}
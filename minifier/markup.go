@@ -0,0 +1,314 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: tokenizador simples de markup (XML/HTML) partilhado pelas funções que
+//          precisam de conhecer a estrutura dos elementos (formatação, etc.).
+//          Não valida nada: divide o input em texto, tags, comentários, CDATA,
+//          processing instructions e declarações, guardando sempre o texto original.
+// License: MIT
+
+package minifier
+
+import "strings"
+
+type markupKind int
+
+const (
+    markupText     markupKind = iota // texto entre tags
+    markupStart                      // <a ...>
+    markupEnd                        // </a>
+    markupEmpty                      // <a .../>
+    markupComment                    // <!-- ... -->
+    markupCDATA                      // <![CDATA[ ... ]]>
+    markupPI                         // <? ... ?>
+    markupDecl                       // <!DOCTYPE ...> e outras declarações <! ... >
+    markupRawText                    // conteúdo de <script>/<style>/<pre>/<textarea>/<title> (só em HTML)
+)
+
+type markupAttr struct {
+    name     string
+    value    string // valor sem aspas (tal como está escrito, sem descodificar entidades)
+    quote    byte   // '"', '\'' ou 0 (sem aspas / sem valor)
+    noValue  bool   // atributo booleano sem '=' (HTML)
+    namePos  int    // offset do nome dentro da tag (a contar do '<')
+    valuePos int    // offset do valor dentro da tag (a seguir à aspa, se houver)
+}
+
+type markupToken struct {
+    kind  markupKind
+    raw   string // texto original completo do token
+    name  string // nome do elemento (tags) ou do elemento pai (markupRawText)
+    attrs []markupAttr
+    pos   int // offset em bytes no input
+}
+
+// tokenizeMarkup divide o input em tokens. Em modo html os nomes dos elementos
+// ficam em minúsculas e o conteúdo de script/style/pre/textarea/title é devolvido
+// como um único token markupRawText.
+func tokenizeMarkup(input string, html bool) []markupToken {
+    var toks []markupToken
+    n := len(input)
+    textStart := 0
+
+    flushText := func(end int) {
+        if end > textStart {
+            toks = append(toks, markupToken{kind: markupText, raw: input[textStart:end], pos: textStart})
+        }
+    }
+
+    // scanUntil devolve o índice logo a seguir ao terminador (ou n se não existir)
+    scanUntil := func(from int, term string) int {
+        k := strings.Index(input[from:], term)
+        if k < 0 {
+            return n
+        }
+        return from + k + len(term)
+    }
+
+    for i := 0; i < n; {
+        if input[i] != '<' {
+            i++
+            continue
+        }
+        rest := input[i:]
+
+        var tok markupToken
+        var end int
+
+        switch {
+        case strings.HasPrefix(rest, "<!--"):
+            end = scanUntil(i+4, "-->")
+            tok = markupToken{kind: markupComment}
+        case strings.HasPrefix(rest, "<![CDATA["):
+            end = scanUntil(i+9, "]]>")
+            tok = markupToken{kind: markupCDATA}
+        case strings.HasPrefix(rest, "<?"):
+            end = scanUntil(i+2, "?>")
+            tok = markupToken{kind: markupPI}
+        case strings.HasPrefix(rest, "<!"):
+            end = scanDeclEnd(input, i+2)
+            tok = markupToken{kind: markupDecl}
+        case len(rest) > 1 && rest[1] == '/':
+            end = scanUntil(i+2, ">")
+            name := strings.TrimRight(strings.TrimSpace(input[i+2:end]), ">")
+            name = strings.TrimSpace(name)
+            if html {
+                name = strings.ToLower(name)
+            }
+            tok = markupToken{kind: markupEnd, name: name}
+        case len(rest) > 1 && isMarkupNameStart(rest[1]):
+            end = scanTagEnd(input, i+1)
+            name, attrs, selfClosing := parseMarkupTag(input[i+1 : end])
+            if html {
+                name = strings.ToLower(name)
+            }
+            tok = markupToken{kind: markupStart, name: name, attrs: attrs}
+            if selfClosing {
+                tok.kind = markupEmpty
+            }
+        default:
+            // '<' solto: faz parte do texto
+            i++
+            continue
+        }
+
+        flushText(i)
+        tok.raw = input[i:end]
+        tok.pos = i
+        toks = append(toks, tok)
+        i = end
+        textStart = i
+
+        // conteúdo "raw" em HTML: vai até </nome (sem distinguir maiúsculas)
+        if html && tok.kind == markupStart && isHTMLRawTextElement(tok.name) {
+            closeAt := indexFold(input[i:], "</"+tok.name)
+            if closeAt < 0 {
+                closeAt = n - i
+            }
+            if closeAt > 0 {
+                toks = append(toks, markupToken{kind: markupRawText, raw: input[i : i+closeAt], name: tok.name, pos: i})
+            }
+            i += closeAt
+            textStart = i
+        }
+    }
+
+    flushText(n)
+    return toks
+}
+
+// scanTagEnd devolve o índice a seguir ao '>' que fecha a tag, respeitando aspas.
+func scanTagEnd(s string, from int) int {
+    quote := byte(0)
+    for j := from; j < len(s); j++ {
+        c := s[j]
+        if quote != 0 {
+            if c == quote {
+                quote = 0
+            }
+            continue
+        }
+        switch c {
+        case '"', '\'':
+            quote = c
+        case '>':
+            return j + 1
+        }
+    }
+    return len(s)
+}
+
+// scanDeclEnd devolve o índice a seguir ao '>' que fecha uma declaração <! ... >,
+// saltando aspas e o subconjunto interno [ ... ] do DOCTYPE.
+func scanDeclEnd(s string, from int) int {
+    quote := byte(0)
+    depth := 0
+    for j := from; j < len(s); j++ {
+        c := s[j]
+        if quote != 0 {
+            if c == quote {
+                quote = 0
+            }
+            continue
+        }
+        switch c {
+        case '"', '\'':
+            quote = c
+        case '[':
+            depth++
+        case ']':
+            if depth > 0 {
+                depth--
+            }
+        case '>':
+            if depth == 0 {
+                return j + 1
+            }
+        }
+    }
+    return len(s)
+}
+
+// parseMarkupTag interpreta o interior de uma tag de abertura (sem o '<'):
+// devolve nome, atributos e se a tag termina em "/>". Os offsets dos atributos
+// contam o '<' que não vem em s.
+func parseMarkupTag(s string) (string, []markupAttr, bool) {
+    s = strings.TrimSuffix(s, ">")
+    selfClosing := false
+    if strings.HasSuffix(s, "/") {
+        selfClosing = true
+        s = s[:len(s)-1]
+    }
+
+    j := 0
+    for j < len(s) && !isMarkupSpace(s[j]) {
+        j++
+    }
+    name := s[:j]
+
+    var attrs []markupAttr
+    for j < len(s) {
+        for j < len(s) && (isMarkupSpace(s[j]) || s[j] == '/') {
+            j++
+        }
+        if j >= len(s) {
+            break
+        }
+        start := j
+        for j < len(s) && !isMarkupSpace(s[j]) && s[j] != '=' {
+            j++
+        }
+        a := markupAttr{name: s[start:j], namePos: start + 1}
+
+        k := j
+        for k < len(s) && isMarkupSpace(s[k]) {
+            k++
+        }
+        if k >= len(s) || s[k] != '=' {
+            a.noValue = true
+            attrs = append(attrs, a)
+            continue
+        }
+        k++
+        for k < len(s) && isMarkupSpace(s[k]) {
+            k++
+        }
+        if k < len(s) && (s[k] == '"' || s[k] == '\'') {
+            q := s[k]
+            e := strings.IndexByte(s[k+1:], q)
+            if e < 0 {
+                e = len(s) - k - 1
+            }
+            a.quote = q
+            a.value = s[k+1 : k+1+e]
+            a.valuePos = k + 2
+            j = k + 1 + e + 1
+        } else {
+            vs := k
+            for k < len(s) && !isMarkupSpace(s[k]) {
+                k++
+            }
+            a.value = s[vs:k]
+            a.valuePos = vs + 1
+            j = k
+        }
+        attrs = append(attrs, a)
+    }
+
+    return name, attrs, selfClosing
+}
+
+// renderMarkupTag volta a escrever uma tag de abertura/vazia a partir do nome e
+// atributos, com um único espaço entre atributos.
+func renderMarkupTag(t markupToken) string {
+    var b strings.Builder
+    b.WriteByte('<')
+    b.WriteString(t.name)
+    for _, a := range t.attrs {
+        b.WriteByte(' ')
+        b.WriteString(a.name)
+        if a.noValue {
+            continue
+        }
+        b.WriteByte('=')
+        if a.quote != 0 {
+            b.WriteByte(a.quote)
+            b.WriteString(a.value)
+            b.WriteByte(a.quote)
+        } else {
+            b.WriteString(a.value)
+        }
+    }
+    if t.kind == markupEmpty {
+        b.WriteByte('/')
+    }
+    b.WriteByte('>')
+    return b.String()
+}
+
+func isMarkupNameStart(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == ':' || c >= 0x80
+}
+
+func isMarkupSpace(c byte) bool {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// elementos HTML cujo conteúdo não é markup (ou não deve ser reinterpretado)
+func isHTMLRawTextElement(name string) bool {
+    switch name {
+    case "script", "style", "pre", "textarea", "title":
+        return true
+    default:
+        return false
+    }
+}
+
+// indexFold é um strings.Index sem distinção de maiúsculas/minúsculas (ASCII).
+func indexFold(s, sub string) int {
+    for i := 0; i+len(sub) <= len(s); i++ {
+        if strings.EqualFold(s[i:i+len(sub)], sub) {
+            return i
+        }
+    }
+    return -1
+}
@@ -22,6 +22,8 @@ const (
     JSON
     XML
     ERROR
+    // acrescentado depois de ERROR para não mudar o valor das constantes existentes
+    SVG
 )
 
 type Options struct {
@@ -59,6 +61,18 @@ type Options struct {
     MinifyJSONScripts bool
     // minificar JSON em atributos data-json="..." / data-json='...' usando MinifyJSON
     MinifyDataJSON    bool
+    // minificar <svg>...</svg> embebido usando MinifySVG (sem remover ids nem
+    // <defs>: outro <svg> da página pode usá-los)
+    MinifyInlineSVG   bool
+    // minificadores usados para o conteúdo embebido acima (por MIME type:
+    // text/css, text/javascript, application/json, image/svg+xml);
+    // nil = DefaultRegistry
+    Registry *Registry
+
+    // --- Referências a caracteres/entidades ---
+    // descodificar &eacute; / &#233; para UTF-8 quando não precisam de escape e
+    // escrever as restantes na forma mais curta (texto e atributos)
+    MinifyHTMLEntities bool
 
     // --- Whitespace & espaçamentos no HTML "de fora" ---
     // aplicar o minificador de whitespace HTML-aware (minifyHTMLWhitespace)
@@ -74,6 +88,59 @@ type Options struct {
     XMLCollapseAttrWhitespace bool // múltiplos espaços entre atributos → um
     XMLCollapseTagWhitespace  bool // remover whitespace entre tags, se for só whitespace
     XMLPreserveCDATA          bool // por defeito true
+    XMLValidate               bool // verificar se o XML é bem formado antes de minificar (ValidateXML)
+    // elementos cujo texto nunca é mexido (além dos que têm xml:space="preserve");
+    // nomes sem prefixo também apanham o nome local (ex: "pre" → <xhtml:pre>)
+    XMLPreserveWhitespaceElements []string
+    // remover declarações xmlns redundantes (já em âmbito) e não usadas
+    XMLCleanNamespaces bool
+    // renomear prefixos para os nomes livres mais curtos (implica XMLCleanNamespaces)
+    XMLShortenPrefixes bool
+    // remover whitespace antes de "/>" e à volta de '=' dentro das tags
+    XMLTrimTagWhitespace bool
+    // escrever elementos vazios <item></item> como <item/>
+    XMLSelfCloseEmpty    bool
+    // usar em cada atributo as aspas que precisam de menos escapes
+    XMLNormalizeQuotes   bool
+    // o mesmo que MinifyHTMLEntities, para XML (só referências numéricas e as
+    // cinco entidades pré-definidas)
+    XMLMinifyEntities    bool
+    // CanonicalizeXML: manter comentários (variantes "WithComments" da C14N)
+    XMLC14NWithComments bool
+    // CanonicalizeXML em modo ExclusiveC14N: prefixos tratados como na C14N
+    // inclusiva (InclusiveNamespaces PrefixList; "#default" = namespace por defeito)
+    XMLC14NInclusivePrefixes []string
+
+    // --- relacionado apenas com JSON ---
+    // reescrever escapes das strings na forma mais curta (\u00e9 → é, \/ → /),
+    // mantendo pares surrogate corretos
+    JSONNormalizeEscapes bool
+    // escapar todo o não-ASCII como \uXXXX (transporte por sistemas legados)
+    JSONASCIIOnly        bool
+
+    // --- relacionado apenas com SVG (MinifySVG) ---
+    // remover <metadata>, <?xml?>/<!DOCTYPE> e elementos/atributos/namespaces
+    // de editores (Inkscape, Sodipodi, Illustrator, Sketch)
+    SVGRemoveMetadata     bool
+    // remover <title> e <desc> (por defeito false, por acessibilidade)
+    SVGRemoveTitleDesc    bool
+    // remover atributos com o valor por omissão (ex: fill-opacity="1")
+    SVGRemoveDefaultAttrs bool
+    // remover elementos de <defs> que não são referenciados
+    SVGRemoveUnusedDefs   bool
+    // remover id="..." não referenciados dentro do próprio SVG
+    // (por defeito false: CSS/JS de fora podem usá-los)
+    SVGRemoveUnusedIDs    bool
+    // casas decimais nos valores numéricos (-1 = não arredondar, só compactar)
+    SVGPrecision          int
+    // compactar d (path data), points, viewBox e transform
+    SVGCompactPaths       bool
+
+    // --- formatação (Beautify*) ---
+    // número de espaços por nível de indentação
+    FormatIndentWidth int
+    // indentar com tabs em vez de espaços (ignora FormatIndentWidth)
+    FormatUseTabs     bool
 
 }
 
@@ -99,6 +166,10 @@ func DefaultOptions() *Options {
         MinifyInlineJS:    true,
         MinifyJSONScripts: true,
         MinifyDataJSON:    true,
+        MinifyInlineSVG:   true,
+
+        // Referências a caracteres/entidades
+        MinifyHTMLEntities: false,
 
         // Whitespace HTML
         CollapseHTMLWhitespace:  true,
@@ -110,6 +181,33 @@ func DefaultOptions() *Options {
         XMLCollapseAttrWhitespace: true,
         XMLCollapseTagWhitespace:  true,
         XMLPreserveCDATA:          true,
+        XMLValidate:               false,
+        XMLPreserveWhitespaceElements: []string{"pre", "programlisting", "screen", "literallayout", "w:t"},
+        XMLCleanNamespaces:            false,
+        XMLShortenPrefixes:            false,
+        XMLTrimTagWhitespace:          false,
+        XMLSelfCloseEmpty:             false,
+        XMLNormalizeQuotes:            false,
+        XMLMinifyEntities:             false,
+        XMLC14NWithComments:           false,
+        XMLC14NInclusivePrefixes:      nil,
+
+        // JSON apenas
+        JSONNormalizeEscapes: false,
+        JSONASCIIOnly:        false,
+
+        // SVG apenas
+        SVGRemoveMetadata:     true,
+        SVGRemoveTitleDesc:    false,
+        SVGRemoveDefaultAttrs: true,
+        SVGRemoveUnusedDefs:   true,
+        SVGRemoveUnusedIDs:    false,
+        SVGPrecision:          3,
+        SVGCompactPaths:       true,
+
+        // Formatação
+        FormatIndentWidth: 2,
+        FormatUseTabs:     false,
     }
 }
 
@@ -124,31 +222,31 @@ func Minify(input string, t Type, opts *Options) (string, error) {
     case JS:
         return MinifyJS(input), nil
     case JSON:
-        return MinifyJSON(input), nil
+        if opts == nil { opts = DefaultOptions() }
+        return MinifyJSONWithOptions(input, opts), nil
     case XML:
         if opts == nil { opts = DefaultOptions() }
+        if opts.XMLValidate {
+            if err := ValidateXML(input); err != nil { return "", err }
+        }
         return MinifyXML(input, opts), nil
+    case SVG:
+        if opts == nil { opts = DefaultOptions() }
+        if opts.XMLValidate {
+            if err := ValidateXML(input); err != nil { return "", err }
+        }
+        return MinifySVG(input, opts), nil
     default:
         return "", errors.New("tipo não suportado")
     }
 }
 
-// Detecta tipo a partir da extensão
+// Detecta tipo a partir da extensão (ver typeTable em types.go)
 func DetectType(path string) Type {
-    switch strings.ToLower(filepath.Ext(path)) {
-    case ".html", ".htm":
-        return HTML
-    case ".css":
-        return CSS
-    case ".js":
-        return JS
-    case ".json":
-        return JSON
-    case ".xml":
-        return XML
-    default:
-        return ERROR
+    if t, ok := extTypes[strings.ToLower(filepath.Ext(path))]; ok {
+        return t
     }
+    return ERROR
 }
 
 // MinifyFile lê, deteta tipo e minifica
@@ -169,10 +267,8 @@ func MinifyReader(r io.Reader, t Type, opts *Options) (string, error) {
 }
 
 // MinifyToWriter lê de reader, minifica e escreve em writer
+// (em streaming para JSON, XML e CSS, ver MinifyStream)
 func MinifyToWriter(r io.Reader, w io.Writer, t Type, opts *Options) error {
     if t == ERROR { return errors.New("não suportado") }
-    out, err := MinifyReader(r, t, opts)
-    if err != nil { return err }
-    _, err = io.WriteString(w, out)
-    return err
+    return MinifyStream(w, r, t, opts)
 }
@@ -0,0 +1,10 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: raceEnabled indica se os testes correm com -race (ver race_test.go)
+// License: MIT
+
+//go:build !race
+
+package minifier
+
+const raceEnabled = false
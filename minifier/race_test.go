@@ -0,0 +1,11 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: raceEnabled indica se os testes correm com -race (ver norace_test.go)
+// License: MIT
+
+//go:build race
+
+package minifier
+
+// com -race, o sync.Pool descarta itens ao acaso e os testes de alocações falham
+const raceEnabled = true
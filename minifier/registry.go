@@ -0,0 +1,181 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: interface Minifier e registo de minificadores por MIME type e extensão,
+//          para poder juntar minificadores próprios (ex: .mjs, .webmanifest) aos
+//          incluídos na biblioteca sem alterar o enum Type. O MinifyHTML usa o
+//          registo (opts.Registry) para o conteúdo embebido em <style>, <script>
+//          e <svg>.
+// License: MIT
+
+package minifier
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Minifier minifica o conteúdo de src para dst. params são os parâmetros do
+// MIME type (ex: charset) mais os passados pelo chamador; podem ser nil.
+type Minifier interface {
+    Minify(dst io.Writer, src io.Reader, params map[string]string) error
+}
+
+// MinifierFunc permite usar uma função como Minifier.
+type MinifierFunc func(dst io.Writer, src io.Reader, params map[string]string) error
+
+func (f MinifierFunc) Minify(dst io.Writer, src io.Reader, params map[string]string) error {
+    return f(dst, src, params)
+}
+
+// typeMinifier é um minificador incluído na biblioteca, exposto como Minifier.
+type typeMinifier struct {
+    t    Type
+    opts *Options
+}
+
+func (m typeMinifier) Minify(dst io.Writer, src io.Reader, _ map[string]string) error {
+    return MinifyToWriter(src, dst, m.t, m.opts)
+}
+
+// Registry associa MIME types e extensões a minificadores. Pode ser usado
+// por várias goroutines em simultâneo.
+type Registry struct {
+    mu     sync.RWMutex
+    byMIME map[string]Minifier
+    byExt  map[string]string // ".mjs" → "text/javascript"
+}
+
+// DefaultRegistry é o registo usado quando opts.Registry é nil.
+var DefaultRegistry = NewRegistry(nil)
+
+// NewRegistry cria um registo com os minificadores da biblioteca (HTML, CSS,
+// JS, JSON, XML e SVG) já registados para os MIME types e extensões de
+// typeTable, configurados com opts.
+func NewRegistry(opts *Options) *Registry {
+    r := &Registry{
+        byMIME: make(map[string]Minifier),
+        byExt:  make(map[string]string),
+    }
+    for _, e := range typeTable {
+        r.Register(e.mime, typeMinifier{e.t, opts}, e.exts...)
+    }
+    return r
+}
+
+// Register associa m ao MIME type e às extensões indicadas (com ou sem '.'),
+// substituindo registos anteriores.
+func (r *Registry) Register(mimeType string, m Minifier, exts ...string) {
+    mimeType = normalizeMIME(mimeType)
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.byMIME[mimeType] = m
+    for _, ext := range exts {
+        r.byExt[normalizeExt(ext)] = mimeType
+    }
+}
+
+// Lookup devolve o minificador de um MIME type (parâmetros como "; charset=utf-8"
+// são ignorados). Um tipo "+json" ou "+xml" sem registo próprio (ex:
+// application/problem+json) usa o de application/json ou application/xml.
+func (r *Registry) Lookup(mimeType string) (Minifier, bool) {
+    mimeType = normalizeMIME(mimeType)
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    m, ok := r.byMIME[mimeType]
+    if !ok {
+        switch {
+        case strings.HasSuffix(mimeType, "+json"):
+            m, ok = r.byMIME["application/json"]
+        case strings.HasSuffix(mimeType, "+xml"):
+            m, ok = r.byMIME["application/xml"]
+        }
+    }
+    return m, ok
+}
+
+// MIMEType devolve o MIME type registado para a extensão de path ("" se nenhum).
+func (r *Registry) MIMEType(path string) string {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.byExt[normalizeExt(filepath.Ext(path))]
+}
+
+// Minify minifica src para dst com o minificador de mimeType. Os parâmetros do
+// MIME type juntam-se a params (os de params têm prioridade).
+func (r *Registry) Minify(mimeType string, dst io.Writer, src io.Reader, params map[string]string) error {
+    m, ok := r.Lookup(mimeType)
+    if !ok {
+        return errors.New("tipo não suportado: " + mimeType)
+    }
+    if _, mp, err := mime.ParseMediaType(mimeType); err == nil && len(mp) > 0 {
+        for k, v := range params {
+            mp[k] = v
+        }
+        params = mp
+    }
+    return m.Minify(dst, src, params)
+}
+
+// MinifyString é o mesmo que Minify para conteúdo em memória.
+func (r *Registry) MinifyString(mimeType, input string) (string, error) {
+    var b strings.Builder
+    if err := r.Minify(mimeType, &b, strings.NewReader(input), nil); err != nil {
+        return "", err
+    }
+    return b.String(), nil
+}
+
+// MinifyFile lê o ficheiro e minifica-o com o minificador da sua extensão.
+func (r *Registry) MinifyFile(path string) (string, error) {
+    mimeType := r.MIMEType(path)
+    if mimeType == "" {
+        return "", errors.New("tipo não suportado")
+    }
+    b, err := os.ReadFile(path)
+    if err != nil { return "", err }
+    return r.MinifyString(mimeType, string(b))
+}
+
+// minifyEmbedded minifica conteúdo embebido (ex: <style> em HTML) com o
+// minificador registado para mimeType. Os minificadores da biblioteca usam as
+// opções do documento "pai"; se não houver minificador ou se der erro, o
+// conteúdo fica como está.
+func minifyEmbedded(mimeType, input string, opts *Options) string {
+    reg := opts.Registry
+    if reg == nil {
+        reg = DefaultRegistry
+    }
+    m, ok := reg.Lookup(mimeType)
+    if !ok {
+        return input
+    }
+    if tm, ok := m.(typeMinifier); ok {
+        tm.opts = opts
+        m = tm
+    }
+    var b strings.Builder
+    if err := m.Minify(&b, strings.NewReader(input), nil); err != nil {
+        return input
+    }
+    return b.String()
+}
+
+func normalizeMIME(mimeType string) string {
+    if k := strings.IndexByte(mimeType, ';'); k >= 0 {
+        mimeType = mimeType[:k]
+    }
+    return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+func normalizeExt(ext string) string {
+    ext = strings.ToLower(ext)
+    if ext != "" && !strings.HasPrefix(ext, ".") {
+        ext = "." + ext
+    }
+    return ext
+}
@@ -0,0 +1,103 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o registo de minificadores por MIME type/extensão
+// License: MIT
+
+package minifier
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// upperMinifier é um minificador de teste: passa tudo a maiúsculas.
+var upperMinifier = MinifierFunc(func(dst io.Writer, src io.Reader, params map[string]string) error {
+    b, err := io.ReadAll(src)
+    if err != nil {
+        return err
+    }
+    _, err = io.WriteString(dst, strings.ToUpper(string(b))+params["charset"])
+    return err
+})
+
+func TestRegistryBuiltins(t *testing.T) {
+    r := NewRegistry(nil)
+
+    tests := []struct {
+        mime     string
+        input    string
+        expected string
+    }{
+        {"text/css", "body {\n  color: red;\n}", "body{color:red;}"},
+        {"text/javascript; charset=utf-8", "var a = 1;\n", "var a=1;"},
+        {"application/json", `{ "a": [1, 2] }`, `{"a":[1,2]}`},
+        {"TEXT/XML", "<a>\n  <b/>\n</a>", "<a><b/></a>"},
+        {"application/problem+json", `{ "title": "x" }`, `{"title":"x"}`},
+        {"application/x-foo+xml", "<a>\n  <b/>\n</a>", "<a><b/></a>"},
+    }
+    for _, tt := range tests {
+        got, err := r.MinifyString(tt.mime, tt.input)
+        if err != nil {
+            t.Fatalf("%s: erro inesperado: %v", tt.mime, err)
+        }
+        if got != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.mime, got, tt.expected)
+        }
+    }
+
+    if _, err := r.MinifyString("text/x-nada", "x"); err == nil {
+        t.Error("esperado erro para MIME type não registado")
+    }
+    if got := r.MIMEType("a/b/style.CSS"); got != "text/css" {
+        t.Errorf("MIMEType: got %q", got)
+    }
+    if got := r.MIMEType("site.webmanifest"); got != "application/manifest+json" {
+        t.Errorf("MIMEType: got %q", got)
+    }
+}
+
+func TestRegistryCustomMinifier(t *testing.T) {
+    r := NewRegistry(nil)
+    r.Register("text/x-test", upperMinifier, "mjs", ".webmanifest")
+
+    if got := r.MIMEType("app.mjs"); got != "text/x-test" {
+        t.Fatalf("MIMEType: got %q", got)
+    }
+    got, err := r.MinifyString("text/x-test; charset=utf-8", "abc")
+    if err != nil || got != "ABCutf-8" {
+        t.Errorf("got %q, %v", got, err)
+    }
+
+    path := filepath.Join(t.TempDir(), "site.webmanifest")
+    if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if got, err := r.MinifyFile(path); err != nil || got != "{}" {
+        t.Errorf("MinifyFile: got %q, %v", got, err)
+    }
+}
+
+func TestMinifyHTMLUsesRegistry(t *testing.T) {
+    r := NewRegistry(nil)
+    r.Register("text/css", upperMinifier)
+    opts := DefaultOptions()
+    opts.Registry = r
+
+    input := `<style>a { color: red }</style><script>var a = 1;</script>`
+    expected := `<style>A { COLOR: RED }</style><script>var a=1;</script>`
+    if got := MinifyHTML(input, opts); got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+
+    // um minificador que falha deixa o conteúdo como está
+    r.Register("text/javascript", MinifierFunc(func(io.Writer, io.Reader, map[string]string) error {
+        return io.ErrUnexpectedEOF
+    }))
+    expected = `<style>A { COLOR: RED }</style><script>var a = 1;</script>`
+    if got := MinifyHTML(input, opts); got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+}
@@ -0,0 +1,220 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: minificação em streaming (io.Reader → io.Writer) para JSON, XML e CSS.
+//          O input é lido em blocos de tamanho fixo e o output é escrito à medida,
+//          por isso a memória usada não depende do tamanho do ficheiro (só do
+//          maior token: string JSON com escapes a reescrever, tag ou comentário
+//          XML). As funções MinifyJSON/MinifyXML/MinifyCSS usam o mesmo código,
+//          logo o resultado é igual nos dois modos (e nas funções Append*).
+// License: MIT
+
+package minifier
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+)
+
+// tamanho dos blocos de leitura/escrita
+const streamChunkSize = 32 * 1024
+
+// MinifyStream minifica de src para dst. JSON, XML e CSS são processados em
+// streaming; os restantes tipos (e XML com XMLValidate, que precisa do documento
+// inteiro antes de escrever) são lidos para memória e minificados com Minify.
+func MinifyStream(dst io.Writer, src io.Reader, t Type, opts *Options) error {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    switch {
+    case t == JSON:
+        return MinifyJSONStream(dst, src, opts)
+    case t == CSS:
+        return MinifyCSSStream(dst, src)
+    case t == XML && !opts.XMLValidate:
+        return MinifyXMLStream(dst, src, opts)
+    }
+    out, err := MinifyReader(src, t, opts)
+    if err != nil { return err }
+    _, err = io.WriteString(dst, out)
+    return err
+}
+
+// MinifyJSONStream é a versão em streaming de MinifyJSONWithOptions.
+func MinifyJSONStream(dst io.Writer, src io.Reader, opts *Options) error {
+    s := newStream(dst, src)
+    defer s.release()
+    return s.finish(minifyJSONStream(s, opts))
+}
+
+// MinifyCSSStream é a versão em streaming de MinifyCSS.
+func MinifyCSSStream(dst io.Writer, src io.Reader) error {
+    s := newStream(dst, src)
+    defer s.release()
+    return s.finish(minifyCSSStream(s))
+}
+
+// MinifyXMLStream é a versão em streaming de MinifyXML. A limpeza de namespaces
+// (XMLCleanNamespaces / XMLShortenPrefixes) precisa de ver o documento inteiro:
+// com essas opções o input é lido todo para memória primeiro.
+func MinifyXMLStream(dst io.Writer, src io.Reader, opts *Options) error {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    if opts.XMLCleanNamespaces || opts.XMLShortenPrefixes {
+        b, err := io.ReadAll(src)
+        if err != nil { return err }
+        src = strings.NewReader(cleanXMLNamespaces(string(b), opts.XMLShortenPrefixes))
+    }
+    s := newStream(dst, src)
+    defer s.release()
+    return s.finish(minifyXMLStream(s, opts))
+}
+
+// stream é a entrada/saída dos minificadores de JSON, XML e CSS. Lê de um
+// io.Reader (com buffer) ou diretamente de um []byte, e escreve num io.Writer
+// (com buffer) ou acrescenta a um []byte (ver append.go). Os objetos e os
+// buffers são reutilizados através de streamPool.
+type stream struct {
+    r   *bufio.Reader // nil → lê de in
+    in  []byte
+    pos int
+
+    w   *bufio.Writer // nil → escreve em out
+    out []byte
+    ew  errWriter
+
+    // buffers reutilizados entre chamadas
+    br      *bufio.Reader
+    bw      *bufio.Writer
+    scratch []byte // output intermédio (tag XML atual, string JSON a reescrever)
+    text    []byte // texto XML entre tags
+    tag     []byte // tag XML atual
+    name    []byte // nome da última tag XML de abertura
+    stack   []bool // pilha de elementos XML abertos
+}
+
+var streamPool = sync.Pool{
+    New: func() any { return new(stream) },
+}
+
+func newStream(dst io.Writer, src io.Reader) *stream {
+    s := streamPool.Get().(*stream)
+    if s.br == nil {
+        s.br = bufio.NewReaderSize(nil, streamChunkSize)
+        s.bw = bufio.NewWriterSize(nil, streamChunkSize)
+    }
+    s.br.Reset(src)
+    s.ew = errWriter{w: dst}
+    s.bw.Reset(&s.ew)
+    s.r, s.w = s.br, s.bw
+    return s
+}
+
+// newBytesStream lê de src e acrescenta o output a dst, sem buffers intermédios.
+func newBytesStream(dst, src []byte) *stream {
+    s := streamPool.Get().(*stream)
+    s.r, s.w = nil, nil
+    s.in, s.pos, s.out = src, 0, dst
+    return s
+}
+
+// release devolve o stream ao pool (sem referências ao input/output).
+func (s *stream) release() {
+    if s.br != nil {
+        s.br.Reset(nil)
+        s.bw.Reset(nil)
+    }
+    s.r, s.w = nil, nil
+    s.in, s.out = nil, nil
+    s.ew = errWriter{}
+    if cap(s.scratch) > 4*streamChunkSize || cap(s.text) > 4*streamChunkSize {
+        // não guardar buffers de documentos invulgarmente grandes
+        s.scratch, s.text = nil, nil
+    }
+    streamPool.Put(s)
+}
+
+// readByte lê o próximo byte. Antes de pedir um bloco novo ao input verifica se
+// a escrita já falhou, para não continuar a ler um ficheiro enorme em vão.
+func (s *stream) readByte() (byte, error) {
+    if s.r == nil {
+        if s.pos >= len(s.in) {
+            return 0, io.EOF
+        }
+        c := s.in[s.pos]
+        s.pos++
+        return c, nil
+    }
+    if s.r.Buffered() == 0 && s.ew.err != nil {
+        return 0, s.ew.err
+    }
+    return s.r.ReadByte()
+}
+
+// peek devolve até n bytes seguintes sem os consumir (menos no fim do input).
+func (s *stream) peek(n int) []byte {
+    if s.r == nil {
+        return s.in[s.pos:min(s.pos+n, len(s.in))]
+    }
+    p, _ := s.r.Peek(n)
+    return p
+}
+
+// hasPrefix indica se os próximos bytes são prefix.
+func (s *stream) hasPrefix(prefix string) bool {
+    return string(s.peek(len(prefix))) == prefix
+}
+
+func (s *stream) skip(n int) {
+    if s.r == nil {
+        s.pos = min(s.pos+n, len(s.in))
+        return
+    }
+    s.r.Discard(n)
+}
+
+func (s *stream) writeByte(c byte) {
+    if s.w == nil {
+        s.out = append(s.out, c)
+        return
+    }
+    s.w.WriteByte(c)
+}
+
+func (s *stream) write(p []byte) {
+    if s.w == nil {
+        s.out = append(s.out, p...)
+        return
+    }
+    s.w.Write(p)
+}
+
+// finish trata o fim do loop de leitura (io.EOF não é erro) e despeja o output.
+func (s *stream) finish(err error) error {
+    if err != nil && err != io.EOF {
+        return err
+    }
+    if s.w == nil {
+        return nil
+    }
+    return s.w.Flush()
+}
+
+// errWriter guarda o primeiro erro de escrita.
+type errWriter struct {
+    w   io.Writer
+    err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+    if e.err != nil {
+        return 0, e.err
+    }
+    n, err := e.w.Write(p)
+    if err != nil {
+        e.err = err
+    }
+    return n, err
+}
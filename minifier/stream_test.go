@@ -0,0 +1,191 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a minificação em streaming
+// License: MIT
+
+package minifier
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestMinifyStreamMatchesString(t *testing.T) {
+    opts := DefaultOptions()
+    opts.XMLSelfCloseEmpty = true
+    opts.XMLTrimTagWhitespace = true
+    opts.XMLMinifyEntities = true
+    opts.JSONNormalizeEscapes = true
+
+    tests := []struct {
+        t        Type
+        input    string
+        expected string
+    }{
+        {JSON, `{ "a" : "café", "b": [1, 2, 3] }`, `{"a":"café","b":[1,2,3]}`},
+        {CSS, "a  {\n  color : red ;\n  content: \" ; \";\n}\n/*! lic */", `a{color:red;content:" ; ";}/*! lic */`},
+        {XML, "<r>\n  <a x = 'caf&#233;' ></a>\n  <b> t&#233;xto </b>\n</r>", `<r><a x='café'/><b>téxto</b></r>`},
+    }
+    for _, tt := range tests {
+        var out bytes.Buffer
+        // um byte de cada vez, para apanhar lookahead entre blocos
+        if err := MinifyStream(&out, iotest.OneByteReader(strings.NewReader(tt.input)), tt.t, opts); err != nil {
+            t.Fatalf("erro inesperado: %v", err)
+        }
+        if out.String() != tt.expected {
+            t.Errorf("stream: got %q, want %q", out.String(), tt.expected)
+        }
+        if got, _ := Minify(tt.input, tt.t, opts); got != tt.expected {
+            t.Errorf("string: got %q, want %q", got, tt.expected)
+        }
+    }
+}
+
+// Documentos maiores do que streamChunkSize: o output é despejado a meio de
+// textos, tags vazias pendentes e comentários.
+func TestMinifyXMLStreamChunks(t *testing.T) {
+    opts := DefaultOptions()
+    opts.XMLSelfCloseEmpty = true
+    opts.XMLMinifyEntities = true
+
+    text := strings.Repeat("ab ]&#93;> &#233; &amp; ", 8000)
+    input := "<r>\n  " + text + "  \n" + strings.Repeat("<e></e>\n", 10000) +
+        "<p xml:space=\"preserve\">  " + text + "</p></r>"
+    want := "<r>" + minifyEntities(strings.TrimSpace(text), false, 0) + strings.Repeat("<e/>", 10000) +
+        "<p xml:space=\"preserve\">" + minifyEntities("  "+text, false, 0) + "</p></r>"
+
+    if got := MinifyXML(input, opts); got != want {
+        t.Errorf("output diferente (len %d, want %d)", len(got), len(want))
+    }
+}
+
+// failWriter falha depois de aceitar n bytes.
+type failWriter struct{ n int }
+
+func (w *failWriter) Write(p []byte) (int, error) {
+    if w.n -= len(p); w.n < 0 {
+        return 0, errors.New("disco cheio")
+    }
+    return len(p), nil
+}
+
+func TestMinifyStreamWriteError(t *testing.T) {
+    src := &repeatReader{head: "[", item: `{"a": 1}, `, tail: "0]", n: 1 << 20}
+    err := MinifyJSONStream(&failWriter{n: 1000}, src, nil)
+    if err == nil || err.Error() != "disco cheio" {
+        t.Fatalf("esperado erro de escrita, got %v", err)
+    }
+    if src.n > 1<<20-1000 {
+        t.Errorf("continuou a ler depois do erro (faltam %d itens)", src.n)
+    }
+}
+
+// repeatReader gera head + n × item + tail sem ter o documento em memória.
+type repeatReader struct {
+    head, item, tail string
+    n                int
+    buf              []byte
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+    for len(r.buf) < len(p) {
+        switch {
+        case r.head != "":
+            r.buf = append(r.buf, r.head...)
+            r.head = ""
+        case r.n > 0:
+            r.buf = append(r.buf, r.item...)
+            r.n--
+        case r.tail != "":
+            r.buf = append(r.buf, r.tail...)
+            r.tail = ""
+        default:
+            if len(r.buf) == 0 {
+                return 0, io.EOF
+            }
+            n := copy(p, r.buf)
+            r.buf = r.buf[n:]
+            return n, nil
+        }
+    }
+    n := copy(p, r.buf)
+    r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+    return n, nil
+}
+
+// heapWriter descarta o output e regista o maior HeapAlloc visto.
+type heapWriter struct {
+    written, next int
+    peak          uint64
+}
+
+func (w *heapWriter) Write(p []byte) (int, error) {
+    w.written += len(p)
+    if w.written >= w.next {
+        var m runtime.MemStats
+        runtime.ReadMemStats(&m)
+        if m.HeapAlloc > w.peak {
+            w.peak = m.HeapAlloc
+        }
+        w.next = w.written + 1<<20
+    }
+    return len(p), nil
+}
+
+var streamDocs = []struct {
+    name             string
+    t                Type
+    head, item, tail string
+}{
+    {"JSON", JSON, "[", `{ "id": 12345, "name": "café com leite", "tags": [ "a", "b" ] },` + "\n", "{}]"},
+    {"XML", XML, "<export>\n", "  <row id=\"1\">\n    <name> caf&#233; </name>\n    <empty></empty>\n  </row>\n", "</export>"},
+    {"CSS", CSS, "", ".item  >  a:hover {\n  color : red ;\n  margin : 0 auto ; /* nota */\n}\n", ""},
+}
+
+// O pico de memória não pode crescer com o input (4 MB ou 32 MB).
+func TestMinifyStreamConstantMemory(t *testing.T) {
+    if testing.Short() {
+        t.Skip("lento")
+    }
+    opts := DefaultOptions()
+    opts.JSONNormalizeEscapes = true
+    opts.XMLSelfCloseEmpty = true
+
+    for _, d := range streamDocs {
+        const limit = 8 << 20
+        for _, size := range []int{4 << 20, 32 << 20} {
+            runtime.GC()
+            src := &repeatReader{head: d.head, item: d.item, tail: d.tail, n: size / len(d.item)}
+            w := &heapWriter{}
+            if err := MinifyStream(w, src, d.t, opts); err != nil {
+                t.Fatal(err)
+            }
+            if w.peak > limit {
+                t.Errorf("%s %d MB: pico de heap %d MB", d.name, size>>20, w.peak>>20)
+            }
+        }
+    }
+}
+
+func BenchmarkMinifyStream(b *testing.B) {
+    opts := DefaultOptions()
+    for _, d := range streamDocs {
+        b.Run(d.name, func(b *testing.B) {
+            const size = 32 << 20
+            b.SetBytes(size)
+            w := &heapWriter{}
+            for i := 0; i < b.N; i++ {
+                src := &repeatReader{head: d.head, item: d.item, tail: d.tail, n: size / len(d.item)}
+                if err := MinifyStream(w, src, d.t, opts); err != nil {
+                    b.Fatal(err)
+                }
+            }
+            b.ReportMetric(float64(w.peak)/(1<<20), "peak-heap-MB")
+        })
+    }
+}
@@ -0,0 +1,478 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: MinifySVG otimiza SVG antes de passar o resultado por MinifyXML:
+//          - remove <metadata>, elementos/atributos/namespaces de editores
+//            (Inkscape, Sodipodi, Illustrator, Sketch), <?xml?> e <!DOCTYPE>
+//          - opcionalmente remove <title>/<desc>
+//          - remove atributos com o valor por omissão (com cuidado com herança)
+//          - remove elementos de <defs> e ids não referenciados
+//          - reduz a precisão numérica dos atributos
+//          - compacta d, points, viewBox e transform (ver svg_path.go)
+// License: MIT
+
+package minifier
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// namespaces usados apenas por editores de SVG
+var svgEditorNamespaces = map[string]bool{
+    "http://www.inkscape.org/namespaces/inkscape":            true,
+    "http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd":     true,
+    "http://ns.adobe.com/AdobeIllustrator/10.0/":             true,
+    "http://ns.adobe.com/AdobeSVGViewerExtensions/3.0/":      true,
+    "http://ns.adobe.com/Extensibility/1.0/":                 true,
+    "http://ns.adobe.com/Flows/1.0/":                         true,
+    "http://ns.adobe.com/GenericCustomNamespace/1.0/":        true,
+    "http://ns.adobe.com/Graphs/1.0/":                        true,
+    "http://ns.adobe.com/ImageReplacement/1.0/":              true,
+    "http://ns.adobe.com/SaveForWeb/1.0/":                    true,
+    "http://ns.adobe.com/Variables/1.0/":                     true,
+    "http://ns.adobe.com/XPath/1.0/":                         true,
+    "http://www.bohemiancoding.com/sketch/ns":                true,
+    "http://www.w3.org/1999/02/22-rdf-syntax-ns#":            true,
+    "http://creativecommons.org/ns#":                         true,
+    "http://purl.org/dc/elements/1.1/":                       true,
+}
+
+// valores por omissão de atributos SVG; inherited indica propriedades herdadas,
+// que só podem ser removidas quando nenhum antepassado as redefine.
+type svgDefault struct {
+    value     string
+    inherited bool
+    elements  string // se não vazio, só se aplica a estes elementos (separados por espaço)
+}
+
+// svgFrame guarda, para cada elemento aberto, as propriedades herdáveis que define.
+type svgFrame struct {
+    name      string
+    inherited map[string]string
+    styled    bool // tem atributo style: não sabemos o que redefine
+}
+
+var svgDefaults = map[string][]svgDefault{
+    "fill":              {{"black", true, ""}, {"#000", true, ""}, {"#000000", true, ""}},
+    "fill-opacity":      {{"1", true, ""}},
+    "fill-rule":         {{"nonzero", true, ""}},
+    "clip-rule":         {{"nonzero", true, ""}},
+    "stroke":            {{"none", true, ""}},
+    "stroke-width":      {{"1", true, ""}},
+    "stroke-opacity":    {{"1", true, ""}},
+    "stroke-linecap":    {{"butt", true, ""}},
+    "stroke-linejoin":   {{"miter", true, ""}},
+    "stroke-miterlimit": {{"4", true, ""}},
+    "stroke-dasharray":  {{"none", true, ""}},
+    "stroke-dashoffset": {{"0", true, ""}},
+    "visibility":        {{"visible", true, ""}},
+    "opacity":           {{"1", false, ""}},
+    "stop-opacity":      {{"1", false, ""}},
+    "display":           {{"inline", false, ""}},
+    "x":                 {{"0", false, "rect use image foreignObject svg"}},
+    "y":                 {{"0", false, "rect use image foreignObject svg"}},
+    "cx":                {{"0", false, "circle ellipse"}},
+    "cy":                {{"0", false, "circle ellipse"}},
+    "preserveAspectRatio": {{"xMidYMid meet", false, ""}, {"xMidYMid", false, ""}},
+    "gradientUnits":     {{"objectBoundingBox", false, ""}},
+    "spreadMethod":      {{"pad", false, ""}},
+    "clipPathUnits":     {{"userSpaceOnUse", false, ""}},
+}
+
+// atributos cujo valor é um número (com unidade opcional) ou lista de números
+var svgNumericAttrs = map[string]bool{
+    "x": true, "y": true, "width": true, "height": true,
+    "cx": true, "cy": true, "r": true, "rx": true, "ry": true,
+    "x1": true, "y1": true, "x2": true, "y2": true, "fx": true, "fy": true,
+    "dx": true, "dy": true, "offset": true, "font-size": true,
+    "stroke-width": true, "stroke-miterlimit": true, "stroke-dashoffset": true,
+    "stroke-dasharray": true, "opacity": true, "fill-opacity": true,
+    "stroke-opacity": true, "stop-opacity": true,
+}
+
+var (
+    reSVGURLRef  = regexp.MustCompile(`url\(\s*['"]?#([^'")\s]+)`)
+    reSVGCSSID   = regexp.MustCompile(`#([A-Za-z_][\w-]*)`)
+    reSVGNumber  = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+)
+
+func MinifySVG(input string, opts *Options) string {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+
+    toks := tokenizeMarkup(input, false)
+
+    // 1) recolher prefixos de editores, ids referenciados e contexto do documento
+    editorPrefixes := map[string]bool{}
+    refs := map[string]bool{}
+    hasStyle := false
+    hasUse := false
+    inStyle := false
+    for _, t := range toks {
+        switch t.kind {
+        case markupStart, markupEmpty:
+            local := svgLocalName(t.name)
+            if local == "style" && t.kind == markupStart {
+                hasStyle = true
+                inStyle = true
+            }
+            if local == "use" {
+                hasUse = true
+            }
+            for _, a := range t.attrs {
+                if strings.HasPrefix(a.name, "xmlns:") && svgEditorNamespaces[a.value] {
+                    editorPrefixes[a.name[len("xmlns:"):]] = true
+                }
+                collectSVGRefs(a.name, a.value, refs)
+            }
+        case markupEnd:
+            if svgLocalName(t.name) == "style" {
+                inStyle = false
+            }
+        case markupText, markupCDATA:
+            for _, m := range reSVGURLRef.FindAllStringSubmatch(t.raw, -1) {
+                refs[m[1]] = true
+            }
+            if inStyle {
+                // seletores #id em <style> (inclui cores #fff, mas só por excesso)
+                for _, m := range reSVGCSSID.FindAllStringSubmatch(t.raw, -1) {
+                    refs[m[1]] = true
+                }
+            }
+        }
+    }
+    // com <style> ou <use> não sabemos de onde vêm as propriedades herdadas
+    canDropInherited := !hasStyle && !hasUse
+
+    // 2) marcar tokens a remover e filtrar atributos
+    skip := make([]bool, len(toks))
+    var stack []svgFrame
+
+    inheritedValue := func(name string) (string, bool) {
+        for k := len(stack) - 1; k >= 0; k-- {
+            if stack[k].styled {
+                return "", true
+            }
+            if v, ok := stack[k].inherited[name]; ok {
+                return v, true
+            }
+        }
+        return "", false
+    }
+
+    for i := 0; i < len(toks); i++ {
+        t := &toks[i]
+        switch t.kind {
+        case markupPI:
+            // <?xml ...?> só é necessário se declarar um encoding diferente de UTF-8
+            if opts.SVGRemoveMetadata && isXMLDeclaration(t.raw) && svgDeclIsUTF8(t.raw) {
+                skip[i] = true
+            }
+        case markupDecl:
+            if opts.SVGRemoveMetadata && strings.HasPrefix(t.raw, "<!DOCTYPE") {
+                skip[i] = true
+            }
+        case markupStart, markupEmpty:
+            if svgShouldRemoveElement(toks, i, stack, editorPrefixes, refs, opts) {
+                end := i
+                if t.kind == markupStart {
+                    end = matchingEnd(toks, i)
+                }
+                for k := i; k <= end && k < len(toks); k++ {
+                    skip[k] = true
+                }
+                i = end
+                continue
+            }
+
+            local := svgLocalName(t.name)
+            f := svgFrame{name: local, inherited: map[string]string{}}
+            var attrs []markupAttr
+            for _, a := range t.attrs {
+                if opts.SVGRemoveMetadata && svgIsEditorAttr(a, editorPrefixes) {
+                    continue
+                }
+                if a.name == "style" {
+                    f.styled = true
+                }
+                if opts.SVGRemoveUnusedIDs && a.name == "id" && !refs[a.value] {
+                    continue
+                }
+                if svgNumericAttrs[a.name] {
+                    a.value = roundSVGNumbers(a.value, opts.SVGPrecision)
+                }
+                if opts.SVGCompactPaths {
+                    switch a.name {
+                    case "d":
+                        a.value = CompactSVGPath(a.value, opts.SVGPrecision)
+                    case "points":
+                        a.value = compactSVGNumberList(a.value, opts.SVGPrecision)
+                    case "viewBox":
+                        a.value = compactSVGViewBox(a.value, opts.SVGPrecision)
+                    case "transform", "gradientTransform", "patternTransform":
+                        a.value = CompactSVGTransform(a.value, opts.SVGPrecision)
+                        if a.value == "" {
+                            // só transformações identidade
+                            continue
+                        }
+                    }
+                }
+                if opts.SVGRemoveDefaultAttrs && !svgIsAnimation(local) {
+                    if def, ok := svgDefaultFor(a.name, local, a.value); ok {
+                        if !def.inherited {
+                            continue
+                        }
+                        if canDropInherited {
+                            if v, set := inheritedValue(a.name); !set || v == a.value {
+                                continue
+                            }
+                        }
+                    }
+                }
+                if _, ok := svgDefaults[a.name]; ok {
+                    f.inherited[a.name] = a.value
+                }
+                attrs = append(attrs, a)
+            }
+            t.attrs = attrs
+            if t.kind == markupStart {
+                stack = append(stack, f)
+            }
+        case markupEnd:
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+        }
+    }
+
+    // 3) <defs> que ficaram vazios
+    if opts.SVGRemoveUnusedDefs {
+        for i, t := range toks {
+            if skip[i] || t.kind != markupStart || svgLocalName(t.name) != "defs" {
+                continue
+            }
+            end := matchingEnd(toks, i)
+            empty := true
+            for k := i + 1; k < end; k++ {
+                if !skip[k] && !(toks[k].kind == markupText && isAllXMLWhitespace(toks[k].raw)) &&
+                    toks[k].kind != markupComment {
+                    empty = false
+                    break
+                }
+            }
+            if empty {
+                for k := i; k <= end && k < len(toks); k++ {
+                    skip[k] = true
+                }
+            }
+        }
+    }
+
+    // 4) serializar e passar pelo minificador de XML
+    var out strings.Builder
+    inStyle = false
+    for i, t := range toks {
+        if skip[i] {
+            continue
+        }
+        switch t.kind {
+        case markupStart, markupEmpty:
+            out.WriteString(renderMarkupTag(t))
+            if t.kind == markupStart && svgLocalName(t.name) == "style" {
+                inStyle = true
+            }
+        case markupEnd:
+            inStyle = false
+            out.WriteString(t.raw)
+        case markupText:
+            if inStyle && !isAllXMLWhitespace(t.raw) {
+                out.WriteString(MinifyCSS(t.raw))
+            } else {
+                out.WriteString(t.raw)
+            }
+        default:
+            out.WriteString(t.raw)
+        }
+    }
+
+    return MinifyXML(out.String(), opts)
+}
+
+// svgShouldRemoveElement decide se o elemento toks[i] (e todo o seu conteúdo) sai.
+func svgShouldRemoveElement(toks []markupToken, i int, stack []svgFrame, editorPrefixes, refs map[string]bool, opts *Options) bool {
+    t := &toks[i]
+    local := svgLocalName(t.name)
+
+    if opts.SVGRemoveMetadata {
+        if local == "metadata" {
+            return true
+        }
+        if k := strings.IndexByte(t.name, ':'); k >= 0 && editorPrefixes[t.name[:k]] {
+            return true
+        }
+    }
+    if opts.SVGRemoveTitleDesc && (local == "title" || local == "desc") {
+        return true
+    }
+    // filhos diretos de <defs> que ninguém referencia (nem a eles, nem a
+    // nenhum elemento lá dentro: <defs><g><path id="p"/></g></defs>)
+    if opts.SVGRemoveUnusedDefs && len(stack) > 0 && stack[len(stack)-1].name == "defs" {
+        if local == "style" || local == "script" {
+            return false
+        }
+        end := i
+        if t.kind == markupStart {
+            end = matchingEnd(toks, i)
+        }
+        for k := i; k <= end && k < len(toks); k++ {
+            for _, a := range toks[k].attrs {
+                if a.name == "id" && refs[a.value] {
+                    return false
+                }
+            }
+        }
+        return true
+    }
+    return false
+}
+
+// collectSVGRefs junta os ids referenciados por um atributo (href="#id", url(#id)).
+func collectSVGRefs(name, value string, refs map[string]bool) {
+    if (name == "href" || strings.HasSuffix(name, ":href")) && strings.HasPrefix(value, "#") {
+        refs[value[1:]] = true
+    }
+    for _, m := range reSVGURLRef.FindAllStringSubmatch(value, -1) {
+        refs[m[1]] = true
+    }
+    // animações: begin="id.click", end="id.end"
+    if name == "begin" || name == "end" {
+        for _, part := range strings.Split(value, ";") {
+            if k := strings.IndexByte(part, '.'); k > 0 {
+                refs[strings.TrimSpace(part[:k])] = true
+            }
+        }
+    }
+}
+
+// svgIsEditorAttr indica se o atributo pertence a um editor (prefixo ou xmlns:prefixo).
+func svgIsEditorAttr(a markupAttr, editorPrefixes map[string]bool) bool {
+    if strings.HasPrefix(a.name, "xmlns:") {
+        return editorPrefixes[a.name[len("xmlns:"):]]
+    }
+    if k := strings.IndexByte(a.name, ':'); k >= 0 {
+        return editorPrefixes[a.name[:k]]
+    }
+    // atributos que o Illustrator acrescenta sem namespace
+    return a.name == "enable-background" || a.name == "data-name"
+}
+
+// svgDefaultFor devolve a entrada de svgDefaults que corresponde ao valor, se existir.
+func svgDefaultFor(name, element, value string) (svgDefault, bool) {
+    value = strings.TrimSpace(value)
+    for _, d := range svgDefaults[name] {
+        if d.value != value {
+            continue
+        }
+        if d.elements != "" && !containsWord(d.elements, element) {
+            continue
+        }
+        return d, true
+    }
+    return svgDefault{}, false
+}
+
+func svgIsAnimation(local string) bool {
+    switch local {
+    case "animate", "animateColor", "animateMotion", "animateTransform", "set":
+        return true
+    default:
+        return false
+    }
+}
+
+// svgDeclIsUTF8 indica se a declaração <?xml ...?> não pede outro encoding.
+func svgDeclIsUTF8(decl string) bool {
+    k := strings.Index(decl, "encoding")
+    if k < 0 {
+        return true
+    }
+    enc := strings.ToLower(decl[k:])
+    return strings.Contains(enc, "utf-8") || strings.Contains(enc, "utf8")
+}
+
+// isXMLDeclaration distingue <?xml ...?> de outras PIs como <?xml-stylesheet ...?>.
+func isXMLDeclaration(pi string) bool {
+    return len(pi) > 5 && strings.HasPrefix(pi, "<?xml") && (isMarkupSpace(pi[5]) || pi[5] == '?')
+}
+
+// svgLocalName devolve o nome sem prefixo de namespace.
+func svgLocalName(name string) string {
+    if k := strings.IndexByte(name, ':'); k >= 0 {
+        return name[k+1:]
+    }
+    return name
+}
+
+// matchingEnd devolve o índice do token que fecha o elemento aberto em toks[i].
+func matchingEnd(toks []markupToken, i int) int {
+    depth := 0
+    for k := i; k < len(toks); k++ {
+        switch toks[k].kind {
+        case markupStart:
+            depth++
+        case markupEnd:
+            depth--
+            if depth == 0 {
+                return k
+            }
+        }
+    }
+    return len(toks) - 1
+}
+
+// roundSVGNumbers arredonda todos os números de um valor para `precision`
+// casas decimais e escreve-os na forma mais curta (0.50 → .5).
+func roundSVGNumbers(value string, precision int) string {
+    return reSVGNumber.ReplaceAllStringFunc(value, func(num string) string {
+        f, err := strconv.ParseFloat(num, 64)
+        if err != nil {
+            return num
+        }
+        return formatSVGNumber(f, precision)
+    })
+}
+
+// formatSVGNumber escreve f com no máximo `precision` casas decimais
+// (precision < 0: sem arredondar), sem zeros supérfluos.
+func formatSVGNumber(f float64, precision int) string {
+    var s string
+    if precision >= 0 {
+        s = strconv.FormatFloat(f, 'f', precision, 64)
+        if strings.Contains(s, ".") {
+            s = strings.TrimRight(s, "0")
+            s = strings.TrimSuffix(s, ".")
+        }
+    } else {
+        s = strconv.FormatFloat(f, 'f', -1, 64)
+    }
+    switch {
+    case s == "-0":
+        s = "0"
+    case strings.HasPrefix(s, "0."):
+        s = s[1:]
+    case strings.HasPrefix(s, "-0."):
+        s = "-" + s[2:]
+    }
+    return s
+}
+
+func containsWord(list, word string) bool {
+    for _, w := range strings.Fields(list) {
+        if w == word {
+            return true
+        }
+    }
+    return false
+}
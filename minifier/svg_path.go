@@ -0,0 +1,505 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: compactação dos atributos geométricos do SVG:
+//          - d (path data): escolhe entre forma absoluta e relativa a mais curta,
+//            converte L em H/V, omite letras de comando repetidas e separadores
+//            desnecessários e arredonda as coordenadas
+//          - points, viewBox e transform: arredondamento e separadores mínimos
+// License: MIT
+
+package minifier
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// número de argumentos por comando de path
+var svgPathArgCount = map[byte]int{
+    'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0,
+}
+
+type svgPathSegment struct {
+    cmd  byte      // comando em maiúscula
+    rel  bool      // escrito em forma relativa no original
+    args []float64 // argumentos tal como escritos
+}
+
+// CompactSVGPath reescreve o atributo d de um <path> na forma mais curta,
+// com `precision` casas decimais (-1 = não arredondar). Se o path não for
+// válido devolve-o sem alterações.
+func CompactSVGPath(d string, precision int) string {
+    segs, ok := parseSVGPath(d)
+    if !ok || len(segs) == 0 {
+        return d
+    }
+    if precision < 0 {
+        // sem arredondamento visível: só limpa o ruído das somas em vírgula flutuante
+        precision = 12
+    }
+
+    var out pathWriter
+    var cx, cy float64 // posição atual (absoluta, como fica no output)
+    var sx, sy float64 // início do subpath atual
+    var ax, ay float64 // posição atual segundo o input (absoluta)
+    var asx, asy float64
+
+    scale := math.Pow(10, float64(precision))
+    round := func(f float64) float64 {
+        return math.Round(f*scale) / scale
+    }
+
+    for _, s := range segs {
+        // argumentos em coordenadas absolutas segundo o input
+        abs := make([]float64, len(s.args))
+        copy(abs, s.args)
+        if s.rel {
+            switch s.cmd {
+            case 'H':
+                abs[0] += ax
+            case 'V':
+                abs[0] += ay
+            case 'A':
+                abs[5] += ax
+                abs[6] += ay
+            default:
+                for k := 0; k+1 < len(abs); k += 2 {
+                    abs[k] += ax
+                    abs[k+1] += ay
+                }
+            }
+        }
+
+        // posição final segundo o input
+        switch s.cmd {
+        case 'Z':
+            ax, ay = asx, asy
+        case 'H':
+            ax = abs[0]
+        case 'V':
+            ay = abs[0]
+        default:
+            ax, ay = abs[len(abs)-2], abs[len(abs)-1]
+        }
+        if s.cmd == 'M' {
+            asx, asy = ax, ay
+        }
+
+        if s.cmd == 'Z' {
+            out.command('z')
+            cx, cy = sx, sy
+            continue
+        }
+
+        // L com uma das coordenadas igual (depois de arredondar) passa a H/V
+        cmd := s.cmd
+        if cmd == 'L' {
+            switch {
+            case round(abs[1]-cy) == 0:
+                cmd, abs = 'H', []float64{abs[0]}
+            case round(abs[0]-cx) == 0:
+                cmd, abs = 'V', []float64{abs[1]}
+            }
+        }
+
+        // valores para a forma absoluta e para a relativa
+        absVals := make([]float64, len(abs))
+        relVals := make([]float64, len(abs))
+        for k := range abs {
+            absVals[k] = round(abs[k])
+            relVals[k] = absVals[k]
+        }
+        switch cmd {
+        case 'H':
+            relVals[0] = round(abs[0] - cx)
+        case 'V':
+            relVals[0] = round(abs[0] - cy)
+        case 'A':
+            relVals[5] = round(abs[5] - cx)
+            relVals[6] = round(abs[6] - cy)
+        default:
+            for k := 0; k+1 < len(abs); k += 2 {
+                relVals[k] = round(abs[k] - cx)
+                relVals[k+1] = round(abs[k+1] - cy)
+            }
+        }
+
+        absStr := out.preview(cmd, absVals, precision)
+        relStr := out.preview(cmd+('a'-'A'), relVals, precision)
+        useRel := len(relStr) < len(absStr)
+
+        vals := absVals
+        letter := cmd
+        if useRel {
+            vals = relVals
+            letter = cmd + ('a' - 'A')
+        }
+        out.segment(letter, vals, precision)
+
+        // posição atual segundo o output
+        switch cmd {
+        case 'H':
+            if useRel {
+                cx += vals[0]
+            } else {
+                cx = vals[0]
+            }
+        case 'V':
+            if useRel {
+                cy += vals[0]
+            } else {
+                cy = vals[0]
+            }
+        default:
+            n := len(vals)
+            if useRel {
+                cx += vals[n-2]
+                cy += vals[n-1]
+            } else {
+                cx, cy = vals[n-2], vals[n-1]
+            }
+        }
+        if cmd == 'M' {
+            sx, sy = cx, cy
+        }
+    }
+
+    return out.String()
+}
+
+// pathWriter escreve o path com o mínimo de letras e separadores.
+type pathWriter struct {
+    b       strings.Builder
+    lastCmd byte
+    lastNum string // último número escrito ("" depois de uma letra, "flag" depois de flag de arco)
+}
+
+// pathImplicit indica se a letra `cmd` pode ser omitida depois de `prev`.
+func pathImplicit(prev, cmd byte) bool {
+    switch {
+    case prev == cmd:
+        return cmd != 'M' && cmd != 'm' && cmd != 'z'
+    case prev == 'M' && cmd == 'L', prev == 'm' && cmd == 'l':
+        return true
+    }
+    return false
+}
+
+func (w *pathWriter) command(c byte) {
+    if c == 'z' && w.lastCmd == 'z' {
+        return
+    }
+    w.b.WriteByte(c)
+    w.lastCmd = c
+    w.lastNum = ""
+}
+
+// preview devolve o texto que segment escreveria (para comparar tamanhos).
+func (w *pathWriter) preview(cmd byte, vals []float64, precision int) string {
+    tmp := pathWriter{lastCmd: w.lastCmd, lastNum: w.lastNum}
+    tmp.segment(cmd, vals, precision)
+    return tmp.b.String()
+}
+
+func (w *pathWriter) segment(cmd byte, vals []float64, precision int) {
+    if !pathImplicit(w.lastCmd, cmd) {
+        w.b.WriteByte(cmd)
+        w.lastNum = ""
+    }
+    // depois de M/m os pares seguintes são L/l implícitos
+    switch cmd {
+    case 'M':
+        w.lastCmd = 'M'
+    case 'm':
+        w.lastCmd = 'm'
+    default:
+        w.lastCmd = cmd
+    }
+
+    isArc := cmd == 'A' || cmd == 'a'
+    for k, v := range vals {
+        if isArc && (k == 3 || k == 4) {
+            // flags: um só dígito, não precisam de separador a seguir
+            flag := "0"
+            if v != 0 {
+                flag = "1"
+            }
+            w.number(flag)
+            w.lastNum = "flag"
+            continue
+        }
+        w.number(formatSVGNumber(v, precision))
+    }
+}
+
+// number escreve um número com separador só quando é necessário.
+func (w *pathWriter) number(s string) {
+    if w.lastNum != "" && w.lastNum != "flag" {
+        needSep := true
+        if s[0] == '-' {
+            needSep = false
+        } else if s[0] == '.' && strings.ContainsAny(w.lastNum, ".eE") {
+            needSep = false
+        }
+        if needSep {
+            w.b.WriteByte(' ')
+        }
+    }
+    w.b.WriteString(s)
+    w.lastNum = s
+}
+
+func (w *pathWriter) String() string {
+    return w.b.String()
+}
+
+// parseSVGPath divide o path em segmentos, expandindo repetições implícitas.
+func parseSVGPath(d string) ([]svgPathSegment, bool) {
+    var segs []svgPathSegment
+    i := 0
+    n := len(d)
+
+    skipSep := func() {
+        for i < n && (isMarkupSpace(d[i]) || d[i] == ',') {
+            i++
+        }
+    }
+
+    var cmd byte
+    rel := false
+    first := true
+    for {
+        skipSep()
+        if i >= n {
+            break
+        }
+        c := d[i]
+        if up := c &^ 0x20; svgPathArgCount[up] > 0 || up == 'Z' {
+            cmd = up
+            rel = c >= 'a'
+            i++
+        } else if cmd == 0 {
+            return nil, false
+        } else if cmd == 'Z' {
+            return nil, false
+        }
+        if first && cmd != 'M' {
+            return nil, false
+        }
+        first = false
+
+        count := svgPathArgCount[cmd]
+        if cmd == 'Z' {
+            segs = append(segs, svgPathSegment{cmd: 'Z', rel: rel})
+            continue
+        }
+
+        // um ou mais grupos de argumentos para este comando
+        for {
+            args := make([]float64, count)
+            for k := 0; k < count; k++ {
+                skipSep()
+                if cmd == 'A' && (k == 3 || k == 4) {
+                    if i >= n || (d[i] != '0' && d[i] != '1') {
+                        return nil, false
+                    }
+                    args[k] = float64(d[i] - '0')
+                    i++
+                    continue
+                }
+                v, size := scanSVGNumber(d[i:])
+                if size == 0 {
+                    return nil, false
+                }
+                args[k] = v
+                i += size
+            }
+            segs = append(segs, svgPathSegment{cmd: cmd, rel: rel, args: args})
+
+            // M seguido de mais pares → L implícito
+            if cmd == 'M' {
+                cmd = 'L'
+            }
+            skipSep()
+            if i >= n || !startsSVGNumber(d[i]) {
+                break
+            }
+        }
+    }
+
+    return segs, true
+}
+
+func startsSVGNumber(c byte) bool {
+    return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.'
+}
+
+// scanSVGNumber lê um número no início de s e devolve o valor e os bytes lidos.
+func scanSVGNumber(s string) (float64, int) {
+    j := 0
+    if j < len(s) && (s[j] == '-' || s[j] == '+') {
+        j++
+    }
+    digits := 0
+    for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+        j++
+        digits++
+    }
+    if j < len(s) && s[j] == '.' {
+        j++
+        for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+            j++
+            digits++
+        }
+    }
+    if digits == 0 {
+        return 0, 0
+    }
+    if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+        k := j + 1
+        if k < len(s) && (s[k] == '-' || s[k] == '+') {
+            k++
+        }
+        if k < len(s) && s[k] >= '0' && s[k] <= '9' {
+            for k < len(s) && s[k] >= '0' && s[k] <= '9' {
+                k++
+            }
+            j = k
+        }
+    }
+    v, err := strconv.ParseFloat(s[:j], 64)
+    if err != nil {
+        return 0, 0
+    }
+    return v, j
+}
+
+// compactSVGNumberList arredonda uma lista de números (points) e junta-os com
+// o mínimo de separadores.
+func compactSVGNumberList(value string, precision int) string {
+    var w pathWriter
+    w.lastCmd = 'x'
+    i := 0
+    for i < len(value) {
+        if isMarkupSpace(value[i]) || value[i] == ',' {
+            i++
+            continue
+        }
+        v, size := scanSVGNumber(value[i:])
+        if size == 0 {
+            return value
+        }
+        w.number(formatSVGNumber(v, precision))
+        i += size
+    }
+    return w.String()
+}
+
+// compactSVGViewBox arredonda os quatro números do viewBox, separados por espaço.
+func compactSVGViewBox(value string, precision int) string {
+    fields := strings.FieldsFunc(value, func(r rune) bool {
+        return r == ' ' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+    })
+    if len(fields) != 4 {
+        return value
+    }
+    for k, f := range fields {
+        v, size := scanSVGNumber(f)
+        if size != len(f) {
+            return value
+        }
+        fields[k] = formatSVGNumber(v, precision)
+    }
+    return strings.Join(fields, " ")
+}
+
+// CompactSVGTransform arredonda e compacta uma lista de transformações,
+// removendo argumentos implícitos e transformações identidade. Translações
+// usam `precision`; os restantes valores usam mais duas casas decimais.
+func CompactSVGTransform(value string, precision int) string {
+    var parts []string
+    rest := strings.TrimSpace(value)
+    for rest != "" {
+        open := strings.IndexByte(rest, '(')
+        close := strings.IndexByte(rest, ')')
+        if open <= 0 || close < open {
+            return value
+        }
+        name := strings.TrimSpace(strings.Trim(rest[:open], ", \t\r\n"))
+        var args []float64
+        for _, f := range strings.FieldsFunc(rest[open+1:close], func(r rune) bool {
+            return r == ' ' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+        }) {
+            v, size := scanSVGNumber(f)
+            if size != len(f) {
+                return value
+            }
+            args = append(args, v)
+        }
+        rest = strings.TrimSpace(rest[close+1:])
+
+        fine := precision
+        if precision >= 0 {
+            fine = precision + 2
+        }
+        var vals []string
+        switch name {
+        case "translate":
+            if len(args) == 2 && formatSVGNumber(args[1], precision) == "0" {
+                args = args[:1]
+            }
+            if len(args) == 1 && formatSVGNumber(args[0], precision) == "0" {
+                continue
+            }
+            for _, a := range args {
+                vals = append(vals, formatSVGNumber(a, precision))
+            }
+        case "scale":
+            if len(args) == 2 && formatSVGNumber(args[0], fine) == formatSVGNumber(args[1], fine) {
+                args = args[:1]
+            }
+            if len(args) == 1 && formatSVGNumber(args[0], fine) == "1" {
+                continue
+            }
+            for _, a := range args {
+                vals = append(vals, formatSVGNumber(a, fine))
+            }
+        case "rotate", "skewX", "skewY":
+            if len(args) >= 1 && formatSVGNumber(args[0], fine) == "0" {
+                continue
+            }
+            for k, a := range args {
+                p := fine
+                if k > 0 {
+                    p = precision // centro de rotação
+                }
+                vals = append(vals, formatSVGNumber(a, p))
+            }
+        case "matrix":
+            if len(args) != 6 {
+                return value
+            }
+            for k, a := range args {
+                p := fine
+                if k >= 4 {
+                    p = precision
+                }
+                vals = append(vals, formatSVGNumber(a, p))
+            }
+            if strings.Join(vals, " ") == "1 0 0 1 0 0" {
+                continue
+            }
+        default:
+            return value
+        }
+
+        var w pathWriter
+        w.lastCmd = 'x'
+        for _, v := range vals {
+            w.number(v)
+        }
+        parts = append(parts, name+"("+w.String()+")")
+    }
+    return strings.Join(parts, "")
+}
@@ -0,0 +1,61 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a compactação de path data, points, viewBox e transform
+// License: MIT
+
+package minifier
+
+import "testing"
+
+func TestCompactSVGPath(t *testing.T) {
+    tests := []struct {
+        name      string
+        input     string
+        precision int
+        expected  string
+    }{
+        {"Lines to H/V", "M 10 10 L 20 10 L 20 20 L 10 20 Z", 3, "M10 10H20V20H10z"},
+        {"Implicit lineto after moveto", "M100,100 L200,200 L300,100", 3, "M100 100 200 200 300 100"},
+        {"Relative is shorter", "M10 10 C 20 20, 40 20, 50 10 S 80 0 90 10", 3, "M10 10c10 10 30 10 40 0S80 0 90 10"},
+        {"Rounding and separators", "M 1.123456 2.987654 l -0.5 -0.5 z m 5 5 h 10 v 10 H 0 V 0 z", 3, "M1.123 2.988l-.5-.5zm5 5h10v10H0V0z"},
+        {"No rounding", "M 1.123456 2.987654 l -0.5 -0.5", -1, "M1.123456 2.987654l-.5-.5"},
+        {"No float noise", "m0.1 0 l0.2 0 l 0.3 0.4", -1, "M.1 0H.3L.6.4"},
+        {"Arc flags", "M10 315 L 110 215 A 30 50 0 0 1 162.55 162.45", 3, "M10 315 110 215a30 50 0 0152.55-52.55"},
+        {"Compact arc flags input", "M0 0a1 1 0 011 1", 3, "M0 0A1 1 0 011 1"},
+        {"Rounding does not drift", "M0 0l0.4 0l0.4 0l0.4 0", 0, "M0 0H0 1 1"},
+        {"Invalid path untouched", "M0 0L", 3, "M0 0L"},
+        {"Must start with moveto", "L10 10", 3, "L10 10"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := CompactSVGPath(tt.input, tt.precision)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+        })
+    }
+}
+
+func TestCompactSVGTransform(t *testing.T) {
+    tests := []struct{ input, expected string }{
+        {"translate(10, 0) scale(2,2)", "translate(10)scale(2)"},
+        {"rotate(0) matrix(1 0 0 1 0 0)", ""},
+        {"rotate(45.123456, 10.12345, -5)", "rotate(45.12346 10.123-5)"},
+        {"skewX(10) foo(1)", "skewX(10) foo(1)"},
+    }
+    for _, tt := range tests {
+        if got := CompactSVGTransform(tt.input, 3); got != tt.expected {
+            t.Errorf("%q: got %q, want %q", tt.input, got, tt.expected)
+        }
+    }
+}
+
+func TestMinifySVGGeometry(t *testing.T) {
+    in := `<svg viewBox="0, 0, 24.0, 24"><polygon points="0,0 10,-10  20.5000,0.5"/>` +
+        `<path d="M 10 10 L 20 10" transform="translate(0 0)"/></svg>`
+    expected := `<svg viewBox="0 0 24 24"><polygon points="0 0 10-10 20.5.5"/><path d="M10 10H20"/></svg>`
+    if got := MinifySVG(in, nil); got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+}
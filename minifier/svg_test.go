@@ -0,0 +1,106 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a otimização de SVG
+// License: MIT
+
+package minifier
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMinifySVG(t *testing.T) {
+    in := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!-- Created with Inkscape -->
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape"
+     xmlns:sodipodi="http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd" width="24.0000" height="24" inkscape:version="1.0">
+  <title>Icon</title>
+  <metadata><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/></metadata>
+  <sodipodi:namedview id="nv" pagecolor="#fff"/>
+  <defs>
+    <linearGradient id="used"><stop offset="0.50000" stop-opacity="1"/></linearGradient>
+    <linearGradient id="unused"/>
+  </defs>
+  <g id="layer1" inkscape:label="Layer" fill-opacity="1" stroke="none">
+    <rect x="0" y="0.0" width="10.123456" height="5" fill="url(#used)" opacity="1"/>
+  </g>
+</svg>`
+    expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24"><title>Icon</title>` +
+        `<defs><linearGradient id="used"><stop offset=".5"/></linearGradient></defs>` +
+        `<g id="layer1"><rect width="10.123" height="5" fill="url(#used)"/></g></svg>`
+    got := MinifySVG(in, nil)
+    if got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+}
+
+func TestMinifySVGOptions(t *testing.T) {
+    in := `<svg><title>T</title><desc>D</desc><g id="a"><rect id="b" width="1.23456"/></g><use href="#b"/></svg>`
+
+    opts := DefaultOptions()
+    opts.SVGRemoveTitleDesc = true
+    opts.SVGRemoveUnusedIDs = true
+    opts.SVGPrecision = 1
+    got := MinifySVG(in, opts)
+    if got != `<svg><g><rect id="b" width="1.2"/></g><use href="#b"/></svg>` {
+        t.Errorf("got %q", got)
+    }
+
+    opts.SVGPrecision = -1
+    if got := MinifySVG(`<svg width="01.2345000"/>`, opts); got != `<svg width="1.2345"/>` {
+        t.Errorf("sem arredondamento: got %q", got)
+    }
+}
+
+func TestMinifySVGInheritedDefaults(t *testing.T) {
+    // fill="black" não pode sair porque o pai define fill="red"
+    in := `<svg><g fill="red"><path fill="black"/></g><path fill="black"/></svg>`
+    got := MinifySVG(in, nil)
+    if got != `<svg><g fill="red"><path fill="black"/></g><path/></svg>` {
+        t.Errorf("got %q", got)
+    }
+    // com <use> não sabemos de onde vem a herança
+    in = `<svg><defs><path id="p" fill="black"/></defs><use href="#p" fill="red"/></svg>`
+    if got := MinifySVG(in, nil); !strings.Contains(got, `fill="black"`) {
+        t.Errorf("fill herdado removido com <use>: %q", got)
+    }
+}
+
+func TestMinifyHTMLInlineSVG(t *testing.T) {
+    in := `<div> <svg width="1.50000"><metadata>x</metadata><path d="M0 0"/></svg> </div>`
+    got := MinifyHTML(in, nil)
+    if got != `<div><svg width="1.5"><path d="M0 0"/></svg></div>` {
+        t.Errorf("got %q", got)
+    }
+    if DetectType("icon.SVG") != SVG {
+        t.Error("DetectType não reconhece .svg")
+    }
+}
+
+func TestMinifySVGDefsSubtreeRefs(t *testing.T) {
+    // o <g> não tem id, mas tem lá dentro um elemento referenciado
+    in := `<svg><defs><g><path id="p" d="M0 0"/></g><g><path id="q"/></g></defs><use href="#p"/></svg>`
+    got := MinifySVG(in, nil)
+    if got != `<svg><defs><g><path id="p" d="M0 0"/></g></defs><use href="#p"/></svg>` {
+        t.Errorf("got %q", got)
+    }
+}
+
+func TestMinifyHTMLInlineSVGSprite(t *testing.T) {
+    // sprite escondido: os símbolos são usados por outro <svg> da página
+    in := `<svg style="display:none"><defs><symbol id="icon"><path d="M0 0"/></symbol></defs></svg>` +
+        `<p><svg><use href="#icon"/></svg></p>`
+    got := MinifyHTML(in, nil)
+    if got != in {
+        t.Errorf("got %q", got)
+    }
+}
+
+func TestMinifyHTMLNestedSVG(t *testing.T) {
+    in := `<div><svg width="2.000"><svg x="1.50"><rect/></svg><rect/></svg><svg/></div><script>var s = "<svg>";</script>`
+    got := MinifyHTML(in, nil)
+    if got != `<div><svg width="2"><svg x="1.5"><rect/></svg><rect/></svg><svg/></div><script>var s="<svg>";</script>` {
+        t.Errorf("got %q", got)
+    }
+}
@@ -0,0 +1,256 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: tabela de MIME types e extensões de cada tipo (usada por DetectType,
+//          TypeForMIME e NewRegistry) e deteção do tipo pelo conteúdo (SniffType),
+//          para stdin e ficheiros sem extensão conhecida.
+// License: MIT
+
+package minifier
+
+import (
+	"bytes"
+	"strings"
+)
+
+// typeTable associa cada MIME type ao tipo e às extensões. O primeiro MIME type
+// de cada tipo é o usado para as extensões.
+var typeTable = []struct {
+    mime string
+    t    Type
+    exts []string
+}{
+    {"text/html", HTML, []string{".html", ".htm", ".shtml"}},
+    {"text/css", CSS, []string{".css"}},
+    {"text/javascript", JS, []string{".js", ".mjs", ".cjs"}},
+    {"application/javascript", JS, nil},
+    {"application/x-javascript", JS, nil},
+    {"application/ecmascript", JS, nil},
+    {"text/ecmascript", JS, nil},
+    {"application/json", JSON, []string{".json", ".map"}},
+    {"application/ld+json", JSON, []string{".jsonld"}},
+    {"application/manifest+json", JSON, []string{".webmanifest"}},
+    {"application/geo+json", JSON, []string{".geojson"}},
+    {"application/xml", XML, []string{".xml", ".xsd", ".xsl", ".xslt", ".rdf", ".kml", ".gpx", ".wsdl"}},
+    {"text/xml", XML, nil},
+    // XHTML tem de continuar XML bem formado: é minificado como XML
+    {"application/xhtml+xml", XML, []string{".xhtml", ".xht"}},
+    {"application/rss+xml", XML, []string{".rss"}},
+    {"application/atom+xml", XML, []string{".atom"}},
+    {"image/svg+xml", SVG, []string{".svg"}},
+}
+
+var extTypes = func() map[string]Type {
+    m := make(map[string]Type)
+    for _, e := range typeTable {
+        for _, ext := range e.exts {
+            m[ext] = e.t
+        }
+    }
+    return m
+}()
+
+// TypeForMIME devolve o tipo de um MIME type (parâmetros como "; charset=utf-8"
+// são ignorados). Os tipos "+json" e "+xml" (ex: application/problem+json)
+// são JSON e XML; os desconhecidos dão ERROR.
+func TypeForMIME(mimeType string) Type {
+    mimeType = normalizeMIME(mimeType)
+    for _, e := range typeTable {
+        if e.mime == mimeType {
+            return e.t
+        }
+    }
+    switch {
+    case strings.HasSuffix(mimeType, "+json"):
+        return JSON
+    case strings.HasSuffix(mimeType, "+xml"):
+        return XML
+    }
+    return ERROR
+}
+
+// bytes analisados por SniffType
+const sniffLen = 1024
+
+// SniffType deteta o tipo pelo conteúdo (só os primeiros 1024 bytes): doctype,
+// declaração <?xml e elemento raiz, '{' / '[' do JSON, regras @ e seletores CSS,
+// palavras-chave de JS. Devolve ERROR se não for claro.
+func SniffType(content []byte) Type {
+    if len(content) > sniffLen {
+        content = content[:sniffLen]
+    }
+    s := string(bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF")))
+    s = strings.TrimLeft(s, " \t\r\n\f")
+    if s == "" {
+        return ERROR
+    }
+
+    switch s[0] {
+    case '<':
+        return sniffMarkup(s)
+    case '[':
+        return JSON
+    case '{':
+        // JSON: {} ou {"chave" ...; em JS um bloco começa por outra coisa
+        rest := strings.TrimLeft(s[1:], " \t\r\n")
+        if rest == "" || rest[0] == '"' || rest[0] == '}' {
+            return JSON
+        }
+        return ERROR
+    case '@':
+        return CSS
+    }
+
+    // comentários de bloco existem em CSS e JS: ver o que vem a seguir
+    for strings.HasPrefix(s, "/*") {
+        end := strings.Index(s, "*/")
+        if end < 0 {
+            return ERROR
+        }
+        s = strings.TrimLeft(s[end+2:], " \t\r\n\f")
+    }
+    if s == "" {
+        return ERROR
+    }
+    if s[0] == '@' {
+        return CSS
+    }
+    if strings.HasPrefix(s, "//") || strings.HasPrefix(s, "#!") || isJSStart(s) {
+        return JS
+    }
+    if looksLikeCSS(s) {
+        return CSS
+    }
+    return ERROR
+}
+
+// sniffMarkup distingue HTML, SVG e XML pelo prólogo e pelo elemento raiz.
+func sniffMarkup(s string) Type {
+    xmlDecl := false
+    doctypeHTML := false
+    for {
+        s = strings.TrimLeft(s, " \t\r\n\f")
+        switch {
+        case strings.HasPrefix(s, "<?"):
+            if strings.HasPrefix(s, "<?xml") {
+                xmlDecl = true
+            }
+            end := strings.Index(s, "?>")
+            if end < 0 {
+                return XML
+            }
+            s = s[end+2:]
+            continue
+        case strings.HasPrefix(s, "<!--"):
+            end := strings.Index(s, "-->")
+            if end < 0 {
+                return ERROR
+            }
+            s = s[end+3:]
+            continue
+        case len(s) >= 9 && strings.EqualFold(s[:9], "<!DOCTYPE"):
+            rest := strings.TrimLeft(s[9:], " \t\r\n")
+            if len(rest) >= 4 && strings.EqualFold(rest[:4], "html") {
+                doctypeHTML = true
+            }
+            end := scanDeclEnd(s, 2)
+            s = s[end:]
+            continue
+        }
+        break
+    }
+
+    // elemento raiz
+    name := ""
+    if len(s) > 1 && s[0] == '<' && isMarkupNameStart(s[1]) {
+        i := 1
+        for i < len(s) && !isMarkupSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+            i++
+        }
+        name = strings.ToLower(s[1:i])
+        if k := strings.IndexByte(name, ':'); k >= 0 {
+            name = name[k+1:]
+        }
+    }
+    switch {
+    case name == "svg":
+        return SVG
+    case xmlDecl:
+        return XML
+    case doctypeHTML || htmlRootElements[name]:
+        return HTML
+    case name != "":
+        return XML
+    }
+    return ERROR
+}
+
+// elementos que, como raiz de um documento sem <?xml, indicam HTML (fragmentos
+// e templates incluídos)
+var htmlRootElements = map[string]bool{
+    "html": true, "head": true, "body": true, "meta": true, "link": true,
+    "title": true, "script": true, "style": true, "div": true, "span": true,
+    "p": true, "a": true, "ul": true, "ol": true, "li": true, "table": true,
+    "form": true, "section": true, "article": true, "header": true,
+    "footer": true, "nav": true, "main": true, "aside": true, "template": true,
+    "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+    "img": true, "br": true, "input": true, "button": true, "pre": true,
+}
+
+// palavras com que um ficheiro JS costuma começar
+var jsStartWords = []string{
+    "var", "let", "const", "function", "import", "export", "class", "async",
+    "if", "for", "while", "return", "new", "this", "window", "document",
+    "console", "module", "require", "define", "(function", "!function",
+    "\"use strict\"", "'use strict'",
+}
+
+func isJSStart(s string) bool {
+    for _, w := range jsStartWords {
+        if strings.HasPrefix(s, w) {
+            if len(s) == len(w) {
+                return true
+            }
+            c := s[len(w)]
+            if !isASCIIAlnum(c) && c != '_' && c != '$' {
+                return true
+            }
+        }
+    }
+    return s[0] == '(' || s[0] == '!'
+}
+
+// looksLikeCSS aceita "seletor { propriedade: valor" no início: o seletor não tem
+// ';' nem atribuições (fora de [atributos]) e o bloco começa por propriedade:.
+func looksLikeCSS(s string) bool {
+    brace := strings.IndexByte(s, '{')
+    if brace <= 0 {
+        return false
+    }
+    depth := 0
+    for i := 0; i < brace; i++ {
+        switch s[i] {
+        case '[':
+            depth++
+        case ']':
+            depth--
+        case ';':
+            return false
+        case '=':
+            if depth == 0 {
+                return false
+            }
+        }
+    }
+    body := strings.TrimLeft(s[brace+1:], " \t\r\n")
+    if body == "" || body[0] == '}' {
+        return true
+    }
+    i := 0
+    for i < len(body) && (isASCIIAlnum(body[i]) || body[i] == '-' || body[i] == '_') {
+        i++
+    }
+    // "--var:" (propriedades personalizadas) ou "prop:"; regras encaixadas
+    // (@media { .a { ...) também são CSS
+    rest := strings.TrimLeft(body[i:], " \t")
+    return (i > 0 && strings.HasPrefix(rest, ":")) || looksLikeCSS(body)
+}
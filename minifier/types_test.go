@@ -0,0 +1,93 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a tabela de tipos e a deteção pelo conteúdo (SniffType)
+// License: MIT
+
+package minifier
+
+import "testing"
+
+func TestSniffType(t *testing.T) {
+    tests := []struct {
+        name     string
+        input    string
+        expected Type
+    }{
+        {"doctype", "<!DOCTYPE html>\n<html><body></body></html>", HTML},
+        {"doctype minúsculas", "\xEF\xBB\xBF  <!doctype html><title>x</title>", HTML},
+        {"fragmento", "<div class=\"a\">x</div>", HTML},
+        {"comentário antes", "<!-- topo -->\n<section></section>", HTML},
+        {"xml", "<?xml version=\"1.0\"?>\n<feed></feed>", XML},
+        {"xml com raiz html", "<?xml version=\"1.0\"?><html xmlns=\"http://www.w3.org/1999/xhtml\"/>", XML},
+        {"raiz desconhecida", "<catalogo><livro/></catalogo>", XML},
+        {"svg", "<svg xmlns=\"http://www.w3.org/2000/svg\"><path d=\"M0 0\"/></svg>", SVG},
+        {"svg com prólogo", "<?xml version=\"1.0\"?>\n<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" \"x.dtd\">\n<svg/>", SVG},
+        {"json objeto", "{\n  \"a\": 1\n}", JSON},
+        {"json vazio", "{}", JSON},
+        {"json array", "[1, 2]", JSON},
+        {"css at-rule", "@charset \"utf-8\";\nbody{}", CSS},
+        {"css regra", "body, .a > p:hover {\n  color: red;\n}", CSS},
+        {"css atributo", "a[href=\"x\"] { color: red }", CSS},
+        {"css variável", ":root { --cor: red }", CSS},
+        {"css encaixado", ".a { .b { color: red } }", CSS},
+        {"css com comentário", "/* tema */\n@media print { a { color: red } }", CSS},
+        {"js var", "var a = 1;", JS},
+        {"js comentário", "// x\nfoo();", JS},
+        {"js comentário bloco", "/* x */\nconst a = {b: 1};", JS},
+        {"js iife", "(function(){})();", JS},
+        {"js use strict", "'use strict';\nx()", JS},
+        {"js bloco", "{ let a = 1; }", ERROR},
+        {"atribuição", "x = { a: 1 };", ERROR},
+        {"vazio", "  \n", ERROR},
+        {"texto", "Olá mundo", ERROR},
+        {"binário", "\x89PNG\r\n\x1a\n", ERROR},
+        {"palavra parecida", "variavel { cor: x", CSS},
+    }
+    for _, tt := range tests {
+        if got := SniffType([]byte(tt.input)); got != tt.expected {
+            t.Errorf("%s: got %v, want %v", tt.name, got, tt.expected)
+        }
+    }
+}
+
+func TestDetectTypeExtensions(t *testing.T) {
+    tests := map[string]Type{
+        "a.HTM":            HTML,
+        "a.shtml":          HTML,
+        "b/app.mjs":        JS,
+        "app.cjs":          JS,
+        "app.js.map":       JSON,
+        "site.webmanifest": JSON,
+        "a.jsonld":         JSON,
+        "mapa.geojson":     JSON,
+        "feed.rss":         XML,
+        "feed.atom":        XML,
+        "esquema.xsd":      XML,
+        "página.xhtml":     XML,
+        "LICENSE":          ERROR,
+        "foto.png":         ERROR,
+    }
+    for path, want := range tests {
+        if got := DetectType(path); got != want {
+            t.Errorf("%s: got %v, want %v", path, got, want)
+        }
+    }
+}
+
+func TestTypeForMIME(t *testing.T) {
+    tests := map[string]Type{
+        "text/html; charset=utf-8":  HTML,
+        "application/x-javascript":  JS,
+        "application/problem+json":  JSON,
+        "application/vnd.api+json":  JSON,
+        "application/xhtml+xml":     XML,
+        "application/soap+xml":      XML,
+        "IMAGE/SVG+XML":             SVG,
+        "image/png":                 ERROR,
+    }
+    for mime, want := range tests {
+        if got := TypeForMIME(mime); got != want {
+            t.Errorf("%s: got %v, want %v", mime, got, want)
+        }
+    }
+}
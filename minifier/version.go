@@ -15,7 +15,7 @@ var (
 )
 
 // ResolvedVersion devolve a versão efetiva da biblioteca.
-func resolvedVersion() string {
+func ResolvedVersion() string {
     // testar cenários:
     // 1) se o release.yml injectou a versão, usa-a
     if Version != "" && Version != "dev" {
@@ -63,7 +63,7 @@ func resolvedBuildDate() string {
 
 // VersionInfo devolve string formatada para o CLI.
 func VersionInfo() string {
-    v := resolvedVersion()
+    v := ResolvedVersion()
     c := resolvedCommit()
     d := resolvedBuildDate()
 
@@ -7,23 +7,44 @@
 //          - remove nós de texto que sejam *apenas* whitespace (indentação)
 //            se opts.CollapseTagWhitespace = true
 //          - preserva sempre o conteúdo de CDATA, processing instructions e <!DOCTYPE ...>
+//          - não toca no texto de elementos com xml:space="preserve" (herdado pelos
+//            descendentes) nem dos listados em opts.XMLPreserveWhitespaceElements
+//          - opcionalmente limpa declarações de namespaces redundantes/não usadas
+//            e encurta prefixos (ver xml_ns.go)
+//          - remove whitespace antes de "/>" e à volta de '=' nas tags
+//            (opts.XMLTrimTagWhitespace)
+//          - opcionalmente escreve elementos vazios como <item/> e escolhe as aspas
+//            de cada atributo que precisam de menos escapes
+//          - opcionalmente minimiza referências a caracteres (ver entities.go)
 //          Não mexe no texto "real" (nós de texto com caracteres não whitespace).
 // License: MIT
 
 package minifier
 
-import "strings"
+import (
+	"bytes"
+	"strings"
+)
 
 func MinifyXML(input string, opts *Options) string {
-    if opts == nil {
-        opts = DefaultOptions()
-    }
-
     var out strings.Builder
-    var textBuf strings.Builder
+    MinifyXMLStream(&out, strings.NewReader(input), opts)
+    return out.String()
+}
 
-    b := []byte(input)
-    n := len(b)
+// minifyXMLStream é o minificador de XML propriamente dito (ver stream.go).
+// O output vai sendo acumulado em out e despejado para s.w sempre que não pode
+// ser reescrito mais (tag atual, possível <item></item> → <item/>); o texto
+// entre tags é despejado em blocos quando é grande.
+func minifyXMLStream(s *stream, opts *Options) error {
+    // a escrever para []byte (AppendXML) o output vai diretamente para s.out
+    direct := s.w == nil
+    out := s.scratch[:0]
+    if direct {
+        out = s.out
+    }
+    textBuf := s.text[:0]
+    textFlushed := false // parte do nó de texto atual já foi escrita
 
     inTag := false       // dentro de <...>
     inAttr := false      // dentro de valor de atributo "..."
@@ -32,91 +53,245 @@ func MinifyXML(input string, opts *Options) string {
     inPI := false        // dentro de <? ... ?>
     inDecl := false      // dentro de <!DOCTYPE ...> ou outras declarações <! ... >
     attrQuote := byte(0)
+    attrStart := 0 // posição (em out) das aspas de abertura do valor atual
+
+    // pilha de elementos abertos: para cada um, se o texto deve ficar intacto
+    // (xml:space="preserve" herdado ou elemento listado em XMLPreserveWhitespaceElements)
+    preserveStack := s.stack[:0]
+    tagBuf := s.tag[:0] // bytes da tag atual (sem o '<'), para saber nome/atributos
+    tagStart := 0       // posição (em out) do '<' da tag atual
+
+    // última tag de abertura escrita: se a tag de fecho vier logo a seguir,
+    // o elemento está vazio e pode passar a <item/>
+    lastStartEnd := -1
+    lastStartName := s.name[:0]
+
+    // devolver os buffers ao stream para serem reutilizados
+    defer func() {
+        if direct {
+            s.out = out
+        } else {
+            s.scratch = out[:0]
+        }
+        s.text, s.tag, s.name, s.stack = textBuf[:0], tagBuf[:0], lastStartName[:0], preserveStack[:0]
+    }()
 
     // helpers para escrever e manter último byte
     var lastOut byte
     writeByte := func(c byte) {
-        out.WriteByte(c)
+        out = append(out, c)
         lastOut = c
     }
     writeString := func(s string) {
-        out.WriteString(s)
+        out = append(out, s...)
         if len(s) > 0 {
             lastOut = s[len(s)-1]
         }
     }
+    // trimTagSpace remove whitespace já escrito dentro da tag atual
+    trimTagSpace := func() {
+        for len(out) > tagStart+1 && isMarkupSpace(out[len(out)-1]) {
+            out = out[:len(out)-1]
+        }
+        lastOut = out[len(out)-1]
+    }
+
+    // flushOut escreve a parte de out que já não vai ser reescrita
+    flushOut := func() {
+        if direct {
+            return
+        }
+        safe := len(out)
+        if inTag {
+            safe = tagStart
+        }
+        if lastStartEnd >= 0 {
+            pending := (inTag && tagStart == lastStartEnd) || (!inTag && len(out) == lastStartEnd)
+            if !pending || !opts.XMLSelfCloseEmpty {
+                lastStartEnd = -1
+            } else if lastStartEnd-1 < safe {
+                safe = lastStartEnd - 1
+            }
+        }
+        if safe <= 0 {
+            return
+        }
+        s.write(out[:safe])
+        out = out[:copy(out, out[safe:])]
+        tagStart -= safe
+        attrStart -= safe
+        if lastStartEnd >= 0 {
+            lastStartEnd -= safe
+        }
+    }
+
+    // entities aplica XMLMinifyEntities a texto / valores de atributos
+    entities := func(v string, quote byte) string {
+        if opts.XMLMinifyEntities {
+            return minifyEntities(v, false, quote)
+        }
+        return v
+    }
+    writeText := func(text []byte) {
+        if opts.XMLMinifyEntities {
+            writeString(minifyEntities(string(text), false, 0))
+            return
+        }
+        out = append(out, text...)
+        if len(text) > 0 {
+            lastOut = text[len(text)-1]
+        }
+    }
+
+    // flush de texto fora de tags / comentários / CDATA; com final=false só
+    // escreve o início do texto (até um ponto seguro) e guarda o resto
+    flushText := func(final bool) {
+        if len(textBuf) == 0 {
+            textFlushed = false
+            return
+        }
+        preserve := len(preserveStack) > 0 && preserveStack[len(preserveStack)-1]
+        collapse := opts.XMLCollapseTagWhitespace && !preserve
+
+        if !final {
+            cut := xmlTextCut(textBuf)
+            if cut <= 0 {
+                return
+            }
+            chunk := textBuf[:cut]
+            if collapse && !textFlushed {
+                chunk = bytes.TrimLeft(chunk, " \t\r\n")
+            }
+            writeText(chunk)
+            textBuf = textBuf[:copy(textBuf, textBuf[cut:])]
+            textFlushed = true
+            return
+        }
+
+        text := textBuf
+        if preserve {
+            // whitespace significativo: escrever tal como está
+            writeText(text)
+        } else if textFlushed {
+            // resto de um texto "real" já escrito em parte
+            if collapse {
+                text = bytes.TrimRight(text, " \t\r\n")
+            }
+            writeText(text)
+        } else if len(bytes.Trim(text, " \t\r\n")) == 0 {
+            // texto é só indentação
+            if opts.XMLCollapseTagWhitespace {
+                // deitamos fora
+            } else {
+                writeText(text)
+            }
+        } else {
+            // texto "real"
+            if opts.XMLCollapseTagWhitespace {
+                // remove apenas whitespace no início/fim,
+                // preservando espaços internos (ex: "Texto com  espaços")
+                writeText(bytes.Trim(text, " \t\r\n"))
+            } else {
+                // modo conservador: não tocar em nada
+                writeText(text)
+            }
+        }
+        textBuf = textBuf[:0]
+        textFlushed = false
+    }
 
-    // flush de texto fora de tags / comentários / CDATA
-		flushText := func() {
-				if textBuf.Len() == 0 {
-						return
-				}
-				s := textBuf.String()
-				if isAllXMLWhitespace(s) {
-						// texto é só indentação
-						if opts.XMLCollapseTagWhitespace {
-								// deitamos fora
-						} else {
-								writeString(s)
-						}
-				} else {
-						// texto "real"
-						if opts.XMLCollapseTagWhitespace {
-								// remove apenas whitespace no início/fim,
-								// preservando espaços internos (ex: "Texto com  espaços")
-								trimmed := strings.Trim(s, " \t\r\n")
-								writeString(trimmed)
-						} else {
-								// modo conservador: não tocar em nada
-								writeString(s)
-						}
-				}
-				textBuf.Reset()
-		}
-
-    for i := 0; i < n; {
-        c := b[i]
+    // endTag atualiza a pilha de elementos quando uma tag termina
+    endTag := func() {
+        if len(tagBuf) > 0 && tagBuf[0] == '/' {
+            if len(preserveStack) > 0 {
+                preserveStack = preserveStack[:len(preserveStack)-1]
+            }
+            name := bytes.TrimSpace(bytes.TrimSuffix(tagBuf[1:], []byte(">")))
+            if opts.XMLSelfCloseEmpty && tagStart == lastStartEnd && bytes.Equal(name, lastStartName) {
+                // <item></item> → <item/>
+                out = append(out[:lastStartEnd-1], '/', '>')
+                lastOut = '>'
+            }
+            lastStartEnd = -1
+            return
+        }
+        name, selfClosing := xmlTagName(tagBuf)
+        lastStartEnd = -1
+        if selfClosing {
+            return
+        }
+        lastStartEnd = len(out)
+        lastStartName = append(lastStartName[:0], name...)
+        preserve := len(preserveStack) > 0 && preserveStack[len(preserveStack)-1]
+        if isXMLPreserveElement(string(name), opts.XMLPreserveWhitespaceElements) {
+            preserve = true
+        }
+        if bytes.Contains(tagBuf, []byte("xml:space")) {
+            _, attrs, _ := parseMarkupTag(string(tagBuf))
+            for _, a := range attrs {
+                if a.name == "xml:space" {
+                    preserve = a.value == "preserve"
+                }
+            }
+        }
+        preserveStack = append(preserveStack, preserve)
+    }
+
+    for {
+        if len(out) >= streamChunkSize {
+            flushOut()
+        }
+        if len(textBuf) >= streamChunkSize {
+            flushText(false)
+        }
+
+        c, err := s.readByte()
+        if err != nil {
+            // flush de texto final
+            flushText(true)
+            inTag = false
+            lastStartEnd = -1
+            flushOut()
+            return err
+        }
 
         // 1) Comentários <!-- ... -->
         if inComment {
-            if i+2 < n && b[i] == '-' && b[i+1] == '-' && b[i+2] == '>' {
+            if c == '-' && s.hasPrefix("->") {
                 if !opts.XMLRemoveComments {
                     writeString("-->")
                 }
                 inComment = false
-                i += 3
+                s.skip(2)
                 continue
             }
             if !opts.XMLRemoveComments {
                 writeByte(c)
             }
-            i++
             continue
         }
 
         // 2) CDATA <![CDATA[ ... ]]>
         if inCDATA {
-            if i+2 < n && b[i] == ']' && b[i+1] == ']' && b[i+2] == '>' {
+            if c == ']' && s.hasPrefix("]>") {
                 writeString("]]>")
                 inCDATA = false
-                i += 3
+                s.skip(2)
                 continue
             }
             writeByte(c)
-            i++
             continue
         }
 
         // 3) Processing instruction <? ... ?>
         if inPI {
-            if i+1 < n && b[i] == '?' && b[i+1] == '>' {
+            if c == '?' && s.hasPrefix(">") {
                 writeString("?>")
                 inPI = false
-                i += 2
+                s.skip(1)
                 continue
             }
             writeByte(c)
-            i++
             continue
         }
 
@@ -126,19 +301,29 @@ func MinifyXML(input string, opts *Options) string {
             if c == '>' {
                 inDecl = false
             }
-            i++
             continue
         }
 
         // 5) Dentro de <tag ...>
         if inTag {
+            tagBuf = append(tagBuf, c)
             if inAttr {
                 // dentro de valor de atributo: não mexer
                 writeByte(c)
                 if c == attrQuote {
                     inAttr = false
+                    if opts.XMLNormalizeQuotes || opts.XMLMinifyEntities {
+                        q, v := attrQuote, string(out[attrStart+1:len(out)-1])
+                        if opts.XMLNormalizeQuotes {
+                            q, v = normalizeAttrQuotes(v)
+                        }
+                        v = entities(v, q)
+                        out = append(out[:attrStart], q)
+                        out = append(out, v...)
+                        out = append(out, q)
+                        lastOut = q
+                    }
                 }
-                i++
                 continue
             }
 
@@ -146,14 +331,25 @@ func MinifyXML(input string, opts *Options) string {
             case '"', '\'':
                 inAttr = true
                 attrQuote = c
+                attrStart = len(out)
                 writeByte(c)
-                i++
             case '>':
+                if opts.XMLTrimTagWhitespace {
+                    trimTagSpace()
+                }
                 writeByte(c)
                 inTag = false
-                i++
+                endTag()
+            case '/', '=':
+                // <a b = "1" /> → <a b="1"/>
+                if opts.XMLTrimTagWhitespace {
+                    trimTagSpace()
+                }
+                writeByte(c)
             case ' ', '\t', '\n', '\r':
-                if opts.XMLCollapseAttrWhitespace {
+                if opts.XMLTrimTagWhitespace && lastOut == '=' {
+                    // whitespace depois de '=' não é necessário
+                } else if opts.XMLCollapseAttrWhitespace {
                     // colapsar whitespace entre nome/atributos em um único espaço
                     if lastOut != ' ' && lastOut != '<' {
                         writeByte(' ')
@@ -161,10 +357,8 @@ func MinifyXML(input string, opts *Options) string {
                 } else {
                     writeByte(c)
                 }
-                i++
             default:
                 writeByte(c)
-                i++
             }
             continue
         }
@@ -172,61 +366,118 @@ func MinifyXML(input string, opts *Options) string {
         // 6) Fora de tags (texto ou início de markup)
         if c == '<' {
             // texto acumulado até aqui
-            flushText()
+            flushText(true)
 
             // Verificar que tipo de markup é
-            if i+1 < n && b[i+1] == '?' {
+            if s.hasPrefix("?") {
                 // processing instruction: <? ... ?>
                 inPI = true
                 writeString("<?")
-                i += 2
+                s.skip(1)
                 continue
             }
 
-            if i+3 < n && b[i+1] == '!' && b[i+2] == '-' && b[i+3] == '-' {
+            if s.hasPrefix("!--") {
                 // comentário <!-- ... -->
                 inComment = true
                 if !opts.XMLRemoveComments {
                     writeString("<!--")
                 }
-                i += 4
+                s.skip(3)
                 continue
             }
 
-            if i+8 < n && b[i+1] == '!' && b[i+2] == '[' &&
-                b[i+3] == 'C' && b[i+4] == 'D' && b[i+5] == 'A' &&
-                b[i+6] == 'T' && b[i+7] == 'A' && b[i+8] == '[' {
+            if s.hasPrefix("![CDATA[") {
                 // <![CDATA[
                 inCDATA = true
                 writeString("<![CDATA[")
-                i += 9
+                s.skip(8)
                 continue
             }
 
-            if i+1 < n && b[i+1] == '!' {
+            if s.hasPrefix("!") {
                 // outra declaração <! ...> (ex: <!DOCTYPE ...>)
                 inDecl = true
                 writeString("<!")
-                i += 2
+                s.skip(1)
                 continue
             }
 
             // Caso normal: tag de elemento (abertura/fecho/empty)
             inTag = true
+            tagBuf = tagBuf[:0]
+            tagStart = len(out)
             writeByte('<')
-            i++
             continue
         }
 
         // Texto normal (fora de qualquer markup especial)
-        textBuf.WriteByte(c)
-        i++
+        textBuf = append(textBuf, c)
     }
+}
 
-    // flush de texto final
-    flushText()
+// xmlTagName devolve o nome de uma tag de abertura (bytes a seguir ao '<') e
+// se é <a/>, como parseMarkupTag mas sem alocar.
+func xmlTagName(tag []byte) ([]byte, bool) {
+    tag = bytes.TrimSuffix(tag, []byte(">"))
+    selfClosing := bytes.HasSuffix(tag, []byte("/"))
+    if selfClosing {
+        tag = tag[:len(tag)-1]
+    }
+    j := 0
+    for j < len(tag) && !isMarkupSpace(tag[j]) {
+        j++
+    }
+    return tag[:j], selfClosing
+}
 
-    return out.String()
+// xmlTextCut devolve até onde um texto grande pode ser escrito sem esperar pelo
+// resto: o corte fica a seguir a uma letra/dígito que não faça parte de uma
+// referência &...; por terminar (0 se não houver ponto seguro).
+func xmlTextCut(b []byte) int {
+    for k := len(b) - 1; k >= 0; k-- {
+        if !isASCIIAlnum(b[k]) {
+            continue
+        }
+        amp := bytes.LastIndexByte(b[:k+1], '&')
+        if amp >= 0 && bytes.IndexByte(b[amp:k+1], ';') < 0 {
+            k = amp
+            continue
+        }
+        return k + 1
+    }
+    return 0
+}
+
+// isXMLPreserveElement indica se o elemento está na lista de elementos com
+// whitespace significativo. Entradas sem prefixo também apanham o nome local
+// (ex: "pre" apanha <xhtml:pre>); entradas com prefixo têm de ser exatas ("w:t").
+func isXMLPreserveElement(name string, list []string) bool {
+    local := name
+    if k := strings.IndexByte(name, ':'); k >= 0 {
+        local = name[k+1:]
+    }
+    for _, e := range list {
+        if e == name || (!strings.Contains(e, ":") && e == local) {
+            return true
+        }
+    }
+    return false
+}
+
+// normalizeAttrQuotes escolhe as aspas que precisam de menos escapes para o
+// valor de um atributo (sem aspas) e devolve o valor reescrito para elas.
+// Em empate ficam as aspas duplas.
+func normalizeAttrQuotes(value string) (byte, string) {
+    r := strings.NewReplacer(
+        "&quot;", `"`, "&#34;", `"`, "&#x22;", `"`,
+        "&apos;", "'", "&#39;", "'", "&#x27;", "'",
+    )
+    raw := r.Replace(value)
+    if strings.Count(raw, `"`) <= strings.Count(raw, "'") {
+        return '"', strings.ReplaceAll(raw, `"`, "&quot;")
+    }
+    return '\'', strings.ReplaceAll(raw, "'", "&apos;")
 }
 
 // isAllXMLWhitespace devolve true se a string for apenas espaço/tab/newline.
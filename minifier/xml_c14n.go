@@ -0,0 +1,339 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: CanonicalizeXML produz a forma canónica de um documento XML completo
+//          (para assinaturas XML-DSig), segundo Canonical XML 1.0, Canonical XML 1.1
+//          ou Exclusive XML Canonicalization 1.0:
+//          - remove a declaração <?xml ...?> e o <!DOCTYPE>
+//          - normaliza fins de linha (CRLF/CR → LF) e valores de atributos
+//          - substitui referências a caracteres/entidades e CDATA pelo texto
+//            correspondente, re-escapado da forma canónica
+//          - escreve <a/> como <a></a>
+//          - ordena declarações de namespace e atributos e remove declarações
+//            supérfluas (na exclusiva, só ficam as visivelmente utilizadas)
+//          Para o documento inteiro, C14N 1.0 e 1.1 dão o mesmo resultado (as
+//          diferenças da 1.1 — xml:id, xml:base — só afetam subconjuntos).
+//          Não são suportados DTDs: atributos por defeito e entidades declaradas
+//          no DOCTYPE não são aplicados (referências a essas entidades dão erro).
+// License: MIT
+
+package minifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// C14NMethod escolhe o algoritmo de canonicalização.
+type C14NMethod int
+
+const (
+    C14N10        C14NMethod = iota // http://www.w3.org/TR/2001/REC-xml-c14n-20010315
+    C14N11                          // http://www.w3.org/2006/12/xml-c14n11
+    ExclusiveC14N                   // http://www.w3.org/2001/10/xml-exc-c14n#
+)
+
+const xmlNamespaceURI = "http://www.w3.org/XML/1998/namespace"
+
+// CanonicalizeXML devolve a forma canónica do documento. Os comentários só são
+// mantidos com opts.XMLC14NWithComments. O input tem de ser XML bem formado
+// (é verificado com ValidateXML).
+func CanonicalizeXML(input string, method C14NMethod, opts *Options) (string, error) {
+    if opts == nil {
+        opts = DefaultOptions()
+    }
+    input = strings.TrimPrefix(input, "\uFEFF")
+    if err := ValidateXML(input); err != nil {
+        return "", err
+    }
+    // fim de linha como um parser XML: CRLF e CR isolado passam a LF
+    input = strings.ReplaceAll(input, "\r\n", "\n")
+    input = strings.ReplaceAll(input, "\r", "\n")
+
+    c := c14nWriter{
+        method:       method,
+        withComments: opts.XMLC14NWithComments,
+        inclusive:    map[string]bool{},
+    }
+    for _, p := range opts.XMLC14NInclusivePrefixes {
+        if p == "#default" {
+            p = ""
+        }
+        c.inclusive[p] = true
+    }
+
+    depth := 0
+    seenRoot := false
+    for _, t := range tokenizeMarkup(input, false) {
+        switch t.kind {
+        case markupStart, markupEmpty:
+            if err := c.startElement(t); err != nil {
+                return "", err
+            }
+            seenRoot = true
+            if t.kind == markupEmpty {
+                c.endElement(t.name)
+            } else {
+                depth++
+            }
+        case markupEnd:
+            c.endElement(t.name)
+            depth--
+        case markupText:
+            if depth == 0 {
+                continue // whitespace fora do elemento raiz
+            }
+            s, err := decodeXMLRefs(t.raw, false)
+            if err != nil {
+                return "", err
+            }
+            c.buf.WriteString(escapeC14NText(s))
+        case markupCDATA:
+            c.buf.WriteString(escapeC14NText(t.raw[len("<![CDATA[") : len(t.raw)-len("]]>")]))
+        case markupComment:
+            if c.withComments {
+                c.outside(t.raw, depth, seenRoot)
+            }
+        case markupPI:
+            body := t.raw[2 : len(t.raw)-2]
+            target, data := body, ""
+            if k := strings.IndexAny(body, " \t\n"); k >= 0 {
+                target = body[:k]
+                data = strings.TrimLeft(body[k:], " \t\n")
+            }
+            if target == "xml" {
+                continue // declaração XML
+            }
+            pi := "<?" + target
+            if data != "" {
+                pi += " " + data
+            }
+            c.outside(pi+"?>", depth, seenRoot)
+        case markupDecl:
+            // <!DOCTYPE ...> não faz parte da forma canónica
+        }
+    }
+
+    return c.buf.String(), nil
+}
+
+// c14nWriter guarda o output e os âmbitos de namespaces durante a canonicalização.
+type c14nWriter struct {
+    buf          strings.Builder
+    method       C14NMethod
+    withComments bool
+    inclusive    map[string]bool // InclusiveNamespaces PrefixList (só ExclusiveC14N)
+
+    declared []map[string]string // declarações de cada elemento aberto (prefixo → URI)
+    rendered []map[string]string // declarações escritas no output por cada elemento
+}
+
+// outside escreve um comentário/PI; fora do elemento raiz fica separado por LF.
+func (c *c14nWriter) outside(s string, depth int, seenRoot bool) {
+    switch {
+    case depth > 0:
+        c.buf.WriteString(s)
+    case seenRoot:
+        c.buf.WriteString("\n" + s)
+    default:
+        c.buf.WriteString(s + "\n")
+    }
+}
+
+// lookupNS procura o URI de um prefixo numa pilha de âmbitos.
+func lookupNS(stack []map[string]string, prefix string) (string, bool) {
+    if prefix == "xml" {
+        return xmlNamespaceURI, true
+    }
+    for k := len(stack) - 1; k >= 0; k-- {
+        if uri, ok := stack[k][prefix]; ok {
+            return uri, true
+        }
+    }
+    return "", false
+}
+
+func (c *c14nWriter) startElement(t markupToken) error {
+    // 1) declarações deste elemento e atributos normais
+    decls := map[string]string{}
+    type c14nAttr struct {
+        name, uri, local, value string
+    }
+    var attrs []c14nAttr
+    for _, a := range t.attrs {
+        value, err := decodeXMLRefs(a.value, true)
+        if err != nil {
+            return err
+        }
+        if prefix, ok := xmlnsPrefix(a.name); ok {
+            if prefix != "xml" {
+                decls[prefix] = value
+            }
+            continue
+        }
+        attrs = append(attrs, c14nAttr{name: a.name, value: value})
+    }
+    c.declared = append(c.declared, decls)
+
+    for i := range attrs {
+        prefix := qnamePrefix(attrs[i].name)
+        attrs[i].local = attrs[i].name[len(prefix):]
+        if prefix != "" {
+            uri, ok := lookupNS(c.declared, prefix)
+            if !ok {
+                return fmt.Errorf("prefixo de namespace não declarado: %q", prefix)
+            }
+            attrs[i].uri = uri
+            attrs[i].local = attrs[i].local[1:]
+        }
+    }
+    elemPrefix := qnamePrefix(t.name)
+    if elemPrefix != "" {
+        if _, ok := lookupNS(c.declared, elemPrefix); !ok {
+            return fmt.Errorf("prefixo de namespace não declarado: %q", elemPrefix)
+        }
+    }
+
+    // 2) declarações a escrever
+    out := map[string]string{}
+    if c.method == ExclusiveC14N {
+        utilized := map[string]bool{elemPrefix: true}
+        for _, a := range attrs {
+            if p := qnamePrefix(a.name); p != "" {
+                utilized[p] = true
+            }
+        }
+        for p := range c.inclusive {
+            utilized[p] = true
+        }
+        for p := range utilized {
+            if p == "xml" {
+                continue
+            }
+            uri, inScope := lookupNS(c.declared, p)
+            if !inScope && p != "" {
+                continue
+            }
+            prev, wasRendered := lookupNS(c.rendered, p)
+            if p == "" && uri == "" && (!wasRendered || prev == "") {
+                continue // xmlns="" só quando anula um default escrito antes
+            }
+            if !wasRendered || prev != uri {
+                out[p] = uri
+            }
+        }
+    } else {
+        parent := c.declared[:len(c.declared)-1]
+        for p, uri := range decls {
+            prev, ok := lookupNS(parent, p)
+            if p == "" && uri == "" && (!ok || prev == "") {
+                continue
+            }
+            if !ok || prev != uri {
+                out[p] = uri
+            }
+        }
+    }
+    c.rendered = append(c.rendered, out)
+
+    // 3) ordem canónica: namespaces por prefixo (default primeiro), atributos
+    // por URI do namespace e depois nome local
+    prefixes := make([]string, 0, len(out))
+    for p := range out {
+        prefixes = append(prefixes, p)
+    }
+    sort.Strings(prefixes)
+    sort.Slice(attrs, func(i, j int) bool {
+        if attrs[i].uri != attrs[j].uri {
+            return attrs[i].uri < attrs[j].uri
+        }
+        return attrs[i].local < attrs[j].local
+    })
+
+    c.buf.WriteString("<" + t.name)
+    for _, p := range prefixes {
+        name := "xmlns"
+        if p != "" {
+            name += ":" + p
+        }
+        c.buf.WriteString(" " + name + `="` + escapeC14NAttr(out[p]) + `"`)
+    }
+    for _, a := range attrs {
+        c.buf.WriteString(" " + a.name + `="` + escapeC14NAttr(a.value) + `"`)
+    }
+    c.buf.WriteByte('>')
+    return nil
+}
+
+func (c *c14nWriter) endElement(name string) {
+    c.buf.WriteString("</" + name + ">")
+    if len(c.declared) > 0 {
+        c.declared = c.declared[:len(c.declared)-1]
+        c.rendered = c.rendered[:len(c.rendered)-1]
+    }
+}
+
+// decodeXMLRefs substitui as referências &...; pelo texto correspondente. Em
+// valores de atributos (attr=true) o whitespace literal passa a espaço, como
+// na normalização de atributos CDATA do XML.
+func decodeXMLRefs(s string, attr bool) (string, error) {
+    if !strings.ContainsAny(s, "&\t\n") {
+        return s, nil
+    }
+    var b strings.Builder
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if attr && (c == '\t' || c == '\n') {
+            b.WriteByte(' ')
+            continue
+        }
+        if c != '&' {
+            b.WriteByte(c)
+            continue
+        }
+        end := strings.IndexByte(s[i:], ';')
+        if end < 0 {
+            return "", fmt.Errorf("'&' sem referência terminada em ';'")
+        }
+        ref := s[i+1 : i+end]
+        switch ref {
+        case "lt":
+            b.WriteByte('<')
+        case "gt":
+            b.WriteByte('>')
+        case "amp":
+            b.WriteByte('&')
+        case "apos":
+            b.WriteByte('\'')
+        case "quot":
+            b.WriteByte('"')
+        default:
+            if !strings.HasPrefix(ref, "#") {
+                return "", fmt.Errorf("entidade não suportada na canonicalização: &%s;", ref)
+            }
+            r, ok := parseCharRef(ref[1:])
+            if !ok {
+                return "", fmt.Errorf("referência de carácter inválida: &%s;", ref)
+            }
+            b.WriteRune(r)
+        }
+        i += end
+    }
+    return b.String(), nil
+}
+
+var (
+    c14nTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+    c14nAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;",
+        "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+// escapeC14NText escapa um nó de texto na forma canónica.
+func escapeC14NText(s string) string {
+    return c14nTextEscaper.Replace(s)
+}
+
+// escapeC14NAttr escapa o valor de um atributo na forma canónica.
+func escapeC14NAttr(s string) string {
+    return c14nAttrEscaper.Replace(s)
+}
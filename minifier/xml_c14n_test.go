@@ -0,0 +1,162 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a canonicalização de XML (C14N)
+// License: MIT
+
+package minifier
+
+import "testing"
+
+// Exemplos da recomendação Canonical XML 1.0 (secção 3), sem as partes que
+// dependem do DTD.
+func TestCanonicalizeXML(t *testing.T) {
+    piInput := "<?xml version=\"1.0\"?>\n\n<?xml-stylesheet   href=\"doc.xsl\"\n   type=\"text/xsl\"   ?>\n\n" +
+        "<!DOCTYPE doc SYSTEM \"doc.dtd\">\n\n<doc>Hello, world!<!-- Comment 1 --></doc>\n\n" +
+        "<?pi-without-data     ?>\n\n<!-- Comment 2 -->\n\n<!-- Comment 3 -->"
+
+    tests := []struct {
+        name     string
+        input    string
+        comments bool
+        expected string
+    }{
+        {
+            name:     "PIs, comentários e fora do elemento raiz",
+            input:    piInput,
+            expected: "<?xml-stylesheet href=\"doc.xsl\"\n   type=\"text/xsl\"   ?>\n<doc>Hello, world!</doc>\n<?pi-without-data?>",
+        },
+        {
+            name:     "Com comentários",
+            input:    piInput,
+            comments: true,
+            expected: "<?xml-stylesheet href=\"doc.xsl\"\n   type=\"text/xsl\"   ?>\n<doc>Hello, world!<!-- Comment 1 --></doc>\n<?pi-without-data?>\n<!-- Comment 2 -->\n<!-- Comment 3 -->",
+        },
+        {
+            name: "Tags, atributos e namespaces",
+            input: `<doc>
+   <e1   />
+   <e2   ></e2>
+   <e3   name = "elem3"   id="elem3"   />
+   <e4   name="elem4"   id="elem4"   ></e4>
+   <e5 a:attr="out" b:attr="sorted" attr2="all" attr="I'm"
+      xmlns:b="http://www.ietf.org"
+      xmlns:a="http://www.w3.org"
+      xmlns="http://example.org"/>
+   <e6 xmlns="" xmlns:a="http://www.w3.org">
+      <e7 xmlns="http://www.ietf.org">
+         <e8 xmlns="" xmlns:a="http://www.w3.org">
+            <e9 xmlns="" xmlns:a="http://www.ietf.org"/>
+         </e8>
+      </e7>
+   </e6>
+</doc>`,
+            expected: `<doc>
+   <e1></e1>
+   <e2></e2>
+   <e3 id="elem3" name="elem3"></e3>
+   <e4 id="elem4" name="elem4"></e4>
+   <e5 xmlns="http://example.org" xmlns:a="http://www.w3.org" xmlns:b="http://www.ietf.org" attr="I'm" attr2="all" b:attr="sorted" a:attr="out"></e5>
+   <e6 xmlns:a="http://www.w3.org">
+      <e7 xmlns="http://www.ietf.org">
+         <e8 xmlns="">
+            <e9 xmlns:a="http://www.ietf.org"></e9>
+         </e8>
+      </e7>
+   </e6>
+</doc>`,
+        },
+        {
+            name: "Caracteres, referências e CDATA",
+            input: "<doc>\r\n" +
+                "   <text>First line&#x0d;&#10;Second line</text>\n" +
+                "   <value>&#x32;</value>\n" +
+                "   <compute><![CDATA[value>\"0\" && value<\"10\" ?\"valid\":\"error\"]]></compute>\n" +
+                "   <compute expr='value>\"0\" &amp;&amp; value&lt;\"10\" ?\"valid\":\"error\"'>valid</compute>\n" +
+                "   <norm attr=' &apos;   &#x20;&#13;&#xa;&#9;   &apos; '/>\n" +
+                "</doc>",
+            expected: "<doc>\n" +
+                "   <text>First line&#xD;\nSecond line</text>\n" +
+                "   <value>2</value>\n" +
+                "   <compute>value&gt;\"0\" &amp;&amp; value&lt;\"10\" ?\"valid\":\"error\"</compute>\n" +
+                "   <compute expr=\"value>&quot;0&quot; &amp;&amp; value&lt;&quot;10&quot; ?&quot;valid&quot;:&quot;error&quot;\">valid</compute>\n" +
+                "   <norm attr=\" '    &#xD;&#xA;&#x9;   ' \"></norm>\n" +
+                "</doc>",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.XMLC14NWithComments = tt.comments
+            for _, m := range []C14NMethod{C14N10, C14N11} {
+                got, err := CanonicalizeXML(tt.input, m, opts)
+                if err != nil {
+                    t.Fatalf("erro inesperado: %v", err)
+                }
+                if got != tt.expected {
+                    t.Errorf("método %d:\ngot  %q\nwant %q", m, got, tt.expected)
+                }
+            }
+        })
+    }
+}
+
+func TestCanonicalizeXMLExclusive(t *testing.T) {
+    input := `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`
+
+    tests := []struct {
+        name      string
+        method    C14NMethod
+        inclusive []string
+        expected  string
+    }{
+        {
+            name:     "Inclusiva",
+            method:   C14N10,
+            expected: `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff></n3:stuff></n1:elem2></n0:local>`,
+        },
+        {
+            name:     "Exclusiva",
+            method:   ExclusiveC14N,
+            expected: `<n0:local xmlns:n0="foo:bar"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2></n0:local>`,
+        },
+        {
+            name:      "Exclusiva com InclusiveNamespaces",
+            method:    ExclusiveC14N,
+            inclusive: []string{"n3"},
+            expected:  `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff></n3:stuff></n1:elem2></n0:local>`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.XMLC14NInclusivePrefixes = tt.inclusive
+            got, err := CanonicalizeXML(input, tt.method, opts)
+            if err != nil {
+                t.Fatalf("erro inesperado: %v", err)
+            }
+            if got != tt.expected {
+                t.Errorf("\ngot  %s\nwant %s", got, tt.expected)
+            }
+        })
+    }
+
+    // default namespace: só é escrito onde é usado, e xmlns="" só o anula
+    got, _ := CanonicalizeXML(`<a xmlns="urn:x"><b xmlns=""><c/></b></a>`, ExclusiveC14N, nil)
+    if want := `<a xmlns="urn:x"><b xmlns=""><c></c></b></a>`; got != want {
+        t.Errorf("got %s, want %s", got, want)
+    }
+}
+
+func TestCanonicalizeXMLErrors(t *testing.T) {
+    for _, input := range []string{
+        `<a><b></a>`,
+        `<p:a/>`,
+        `<!DOCTYPE a [<!ENTITY e "x">]><a>&e;</a>`,
+    } {
+        if _, err := CanonicalizeXML(input, C14N10, nil); err == nil {
+            t.Errorf("esperado erro para %q", input)
+        }
+    }
+}
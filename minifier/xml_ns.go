@@ -0,0 +1,251 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: limpeza de namespaces XML usada por MinifyXML:
+//          - remove declarações xmlns redundantes (mesmo prefixo e URI já em âmbito)
+//          - remove declarações xmlns:prefixo que ninguém usa
+//          - opcionalmente troca os prefixos pelos nomes livres mais curtos
+//          O documento resultante é equivalente em termos de namespaces. Prefixos
+//          que aparecem em valores de atributos ou texto (ex: xsi:type="xs:string")
+//          contam como usados e nunca são renomeados.
+// License: MIT
+
+package minifier
+
+import (
+	"regexp"
+	"strings"
+)
+
+// nsDecl é uma declaração xmlns:prefixo="uri" no documento original.
+type nsDecl struct {
+    prefix string
+    uri    string
+    used   bool
+}
+
+// nsScope mapeia prefixo original → declaração em vigor.
+type nsScope map[string]*nsDecl
+
+var reQNamePrefix = regexp.MustCompile(`([A-Za-z_][\w.-]*):[A-Za-z_]`)
+
+// cleanXMLNamespaces aplica a limpeza de namespaces; com shorten=true também
+// encurta os prefixos.
+func cleanXMLNamespaces(input string, shorten bool) string {
+    toks := tokenizeMarkup(input, false)
+
+    // declarações de cada tag (por índice do token)
+    decls := make(map[int][]*nsDecl)
+    protected := map[string]bool{"xml": true, "xmlns": true}
+    var uriOrder []string
+    seenURI := map[string]bool{}
+
+    // 1) análise: resolver cada uso de prefixo para a declaração em vigor
+    var stack []nsScope
+    lookup := func(prefix string) *nsDecl {
+        for k := len(stack) - 1; k >= 0; k-- {
+            if d, ok := stack[k][prefix]; ok {
+                return d
+            }
+        }
+        return nil
+    }
+    use := func(prefix string) {
+        if prefix == "" || prefix == "xml" || prefix == "xmlns" {
+            return
+        }
+        if d := lookup(prefix); d != nil {
+            d.used = true
+        } else {
+            // prefixo não declarado aqui: não pode ser reutilizado por outro
+            protected[prefix] = true
+        }
+    }
+    // prefixos em valores/texto (QNames): contam como usados e não se renomeiam
+    useInValue := func(value string) {
+        for _, m := range reQNamePrefix.FindAllStringSubmatch(value, -1) {
+            if d := lookup(m[1]); d != nil {
+                d.used = true
+                protected[m[1]] = true
+            }
+        }
+    }
+
+    for i, t := range toks {
+        switch t.kind {
+        case markupStart, markupEmpty:
+            scope := nsScope{}
+            for _, a := range t.attrs {
+                prefix, ok := xmlnsPrefix(a.name)
+                if !ok {
+                    continue
+                }
+                d := &nsDecl{prefix: prefix, uri: a.value}
+                scope[prefix] = d
+                decls[i] = append(decls[i], d)
+                if prefix == "" || a.value == "" {
+                    // default namespace e "undeclare" (XML 1.1) ficam sempre
+                    d.used = true
+                    if prefix != "" {
+                        protected[prefix] = true
+                    }
+                }
+                if !seenURI[a.value] && prefix != "" {
+                    seenURI[a.value] = true
+                    uriOrder = append(uriOrder, a.value)
+                }
+            }
+            stack = append(stack, scope)
+            use(qnamePrefix(t.name))
+            for _, a := range t.attrs {
+                if _, ok := xmlnsPrefix(a.name); ok {
+                    continue
+                }
+                use(qnamePrefix(a.name))
+                useInValue(a.value)
+            }
+            if t.kind == markupEmpty {
+                stack = stack[:len(stack)-1]
+            }
+        case markupEnd:
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+        case markupText, markupCDATA:
+            useInValue(t.raw)
+        }
+    }
+
+    // 2) nomes curtos por URI, sem colidir com prefixos protegidos
+    short := map[string]string{}
+    if shorten {
+        n := 0
+        for _, uri := range uriOrder {
+            name := shortPrefixName(n)
+            for protected[name] || strings.HasPrefix(strings.ToLower(name), "xml") {
+                n++
+                name = shortPrefixName(n)
+            }
+            short[uri] = name
+            n++
+        }
+    }
+    rename := func(d *nsDecl, prefix string) string {
+        if d == nil || !shorten || protected[prefix] || prefix == "" {
+            return prefix
+        }
+        if s, ok := short[d.uri]; ok {
+            return s
+        }
+        return prefix
+    }
+    renameQName := func(name string) string {
+        prefix := qnamePrefix(name)
+        if prefix == "" || prefix == "xml" {
+            return name
+        }
+        return rename(lookup(prefix), prefix) + name[len(prefix):]
+    }
+
+    // 3) reescrever, mantendo o âmbito de saída (prefixo final → URI)
+    var out strings.Builder
+    stack = stack[:0]
+    var outStack []map[string]string
+    outLookup := func(prefix string) (string, bool) {
+        for k := len(outStack) - 1; k >= 0; k-- {
+            if uri, ok := outStack[k][prefix]; ok {
+                return uri, true
+            }
+        }
+        return "", false
+    }
+
+    for i, t := range toks {
+        switch t.kind {
+        case markupStart, markupEmpty:
+            scope := nsScope{}
+            for _, d := range decls[i] {
+                scope[d.prefix] = d
+            }
+            stack = append(stack, scope)
+
+            outScope := map[string]string{}
+            var attrs []markupAttr
+            for _, a := range t.attrs {
+                if prefix, ok := xmlnsPrefix(a.name); ok {
+                    d := scope[prefix]
+                    if !d.used {
+                        continue
+                    }
+                    name := rename(d, prefix)
+                    if uri, ok := outLookup(name); ok && uri == d.uri {
+                        continue // redundante
+                    }
+                    if uri, ok := outScope[name]; ok && uri == d.uri {
+                        continue // dois prefixos que passaram a ter o mesmo nome
+                    }
+                    outScope[name] = d.uri
+                    if name == "" {
+                        a.name = "xmlns"
+                    } else {
+                        a.name = "xmlns:" + name
+                    }
+                    attrs = append(attrs, a)
+                    continue
+                }
+                a.name = renameQName(a.name)
+                attrs = append(attrs, a)
+            }
+            outStack = append(outStack, outScope)
+
+            t.name = renameQName(t.name)
+            t.attrs = attrs
+            out.WriteString(renderMarkupTag(t))
+
+            if t.kind == markupEmpty {
+                stack = stack[:len(stack)-1]
+                outStack = outStack[:len(outStack)-1]
+            }
+        case markupEnd:
+            out.WriteString("</" + renameQName(t.name) + ">")
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+                outStack = outStack[:len(outStack)-1]
+            }
+        default:
+            out.WriteString(t.raw)
+        }
+    }
+
+    return out.String()
+}
+
+// xmlnsPrefix devolve o prefixo declarado por um atributo xmlns / xmlns:p.
+func xmlnsPrefix(attr string) (string, bool) {
+    if attr == "xmlns" {
+        return "", true
+    }
+    if strings.HasPrefix(attr, "xmlns:") {
+        return attr[len("xmlns:"):], true
+    }
+    return "", false
+}
+
+// qnamePrefix devolve o prefixo de um nome qualificado ("" se não tiver).
+func qnamePrefix(name string) string {
+    if k := strings.IndexByte(name, ':'); k > 0 {
+        return name[:k]
+    }
+    return ""
+}
+
+// shortPrefixName gera a, b, ..., z, aa, ab, ...
+func shortPrefixName(n int) string {
+    name := ""
+    for {
+        name = string(rune('a'+n%26)) + name
+        n = n/26 - 1
+        if n < 0 {
+            return name
+        }
+    }
+}
@@ -0,0 +1,68 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a limpeza de namespaces XML
+// License: MIT
+
+package minifier
+
+import "testing"
+
+func TestXMLCleanNamespaces(t *testing.T) {
+    tests := []struct {
+        name     string
+        input    string
+        shorten  bool
+        expected string
+    }{
+        {
+            name:     "Redundant declaration removed",
+            input:    `<s:Env xmlns:s="urn:soap"><s:Body xmlns:s="urn:soap"><s:x/></s:Body></s:Env>`,
+            expected: `<s:Env xmlns:s="urn:soap"><s:Body><s:x/></s:Body></s:Env>`,
+        },
+        {
+            name:     "Unused declaration removed",
+            input:    `<r xmlns="urn:d" xmlns:u="urn:unused" xmlns:a="urn:a"><a:x/></r>`,
+            expected: `<r xmlns="urn:d" xmlns:a="urn:a"><a:x/></r>`,
+        },
+        {
+            name:     "Prefix used in attribute value is kept",
+            input:    `<r xmlns:xsi="urn:xsi" xmlns:xs="urn:xs"><v xsi:type="xs:string"/></r>`,
+            expected: `<r xmlns:xsi="urn:xsi" xmlns:xs="urn:xs"><v xsi:type="xs:string"/></r>`,
+        },
+        {
+            name:     "Shadowed prefix keeps its own binding",
+            input:    `<p:r xmlns:p="urn:1"><p:c xmlns:p="urn:2"/></p:r>`,
+            expected: `<p:r xmlns:p="urn:1"><p:c xmlns:p="urn:2"/></p:r>`,
+        },
+        {
+            name:     "Shorten prefixes",
+            input:    `<soapenv:Envelope xmlns:soapenv="urn:soap" xmlns:mytypes="urn:t"><soapenv:Body><mytypes:Item mytypes:id="1"/></soapenv:Body></soapenv:Envelope>`,
+            shorten:  true,
+            expected: `<a:Envelope xmlns:a="urn:soap" xmlns:b="urn:t"><a:Body><b:Item b:id="1"/></a:Body></a:Envelope>`,
+        },
+        {
+            name:     "Shorten avoids protected prefixes",
+            input:    `<long:r xmlns:long="urn:l" xmlns:a="urn:a" t="a:x"><long:c/></long:r>`,
+            shorten:  true,
+            expected: `<b:r xmlns:b="urn:l" xmlns:a="urn:a" t="a:x"><b:c/></b:r>`,
+        },
+        {
+            name:     "Two prefixes for the same URI merge",
+            input:    `<x:r xmlns:x="urn:1"><y:c xmlns:y="urn:1"/></x:r>`,
+            shorten:  true,
+            expected: `<a:r xmlns:a="urn:1"><a:c/></a:r>`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.XMLCleanNamespaces = true
+            opts.XMLShortenPrefixes = tt.shorten
+            got := MinifyXML(tt.input, opts)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+        })
+    }
+}
@@ -6,6 +6,10 @@
 package minifier
 
 import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +45,143 @@ func TestMinifyXML(t *testing.T) {
         })
     }
 }
+
+func TestMinifyXMLPreserveSpace(t *testing.T) {
+    tests := []struct {
+        name     string
+        input    string
+        expected string
+    }{
+        {
+            name:     "xml:space preserve is inherited",
+            input:    "<r>\n <a xml:space=\"preserve\">\n  <b>  x  </b> </a>\n <c> y </c>\n</r>",
+            expected: "<r><a xml:space=\"preserve\">\n  <b>  x  </b> </a><c>y</c></r>",
+        },
+        {
+            name:     "xml:space default resets inheritance",
+            input:    `<r xml:space="preserve"><a xml:space="default"> x </a> </r>`,
+            expected: `<r xml:space="preserve"><a xml:space="default">x</a> </r>`,
+        },
+        {
+            name:     "Listed elements",
+            input:    "<doc>\n <programlisting>  a\n  b </programlisting>\n <w:t> c </w:t>\n</doc>",
+            expected: "<doc><programlisting>  a\n  b </programlisting><w:t> c </w:t></doc>",
+        },
+        {
+            name:     "Local name match",
+            input:    "<html:div> <html:pre> x </html:pre> </html:div>",
+            expected: "<html:div><html:pre> x </html:pre></html:div>",
+        },
+        {
+            name:     "Self-closing element does not open scope",
+            input:    `<r><a xml:space="preserve"/> <b> x </b></r>`,
+            expected: `<r><a xml:space="preserve"/><b>x</b></r>`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := MinifyXML(tt.input, nil)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+        })
+    }
+
+    opts := DefaultOptions()
+    opts.XMLPreserveWhitespaceElements = []string{"code"}
+    if got := MinifyXML("<r><code> x </code><pre> y </pre></r>", opts); got != "<r><code> x </code><pre>y</pre></r>" {
+        t.Errorf("lista configurável: got %q", got)
+    }
+}
+
+func TestMinifyXMLTagRewrites(t *testing.T) {
+    tests := []struct {
+        name      string
+        input     string
+        trim      bool
+        selfClose bool
+        quotes    bool
+        expected  string
+    }{
+        {
+            name:     "Whitespace before /> and around =",
+            input:    `<r><a  b = "1"  c= '2' /><d e ="3" ></d ></r>`,
+            trim:     true,
+            expected: `<r><a b="1" c='2'/><d e="3"></d></r>`,
+        },
+        {
+            name:      "Self-close empty elements",
+            input:     "<r>\n  <item></item>\n  <x a=\"1\"><!-- c --></x>\n  <y> </y>\n  <z>t</z>\n</r>",
+            selfClose: true,
+            expected:  `<r><item/><x a="1"/><y/><z>t</z></r>`,
+        },
+        {
+            name:      "Self-close keeps non-empty and mismatched",
+            input:     `<r><a><b></b></a><c>x</c></r>`,
+            selfClose: true,
+            expected:  `<r><a><b/></a><c>x</c></r>`,
+        },
+        {
+            name:     "Normalize quotes",
+            input:    `<r a='1' b='say "hi"' c="it&apos;s" d='&quot;&quot;&apos;'/>`,
+            quotes:   true,
+            expected: `<r a="1" b='say "hi"' c="it's" d='""&apos;'/>`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := DefaultOptions()
+            opts.XMLTrimTagWhitespace = tt.trim
+            opts.XMLSelfCloseEmpty = tt.selfClose
+            opts.XMLNormalizeQuotes = tt.quotes
+            got := MinifyXML(tt.input, opts)
+            if got != tt.expected {
+                t.Errorf("got %q, want %q", got, tt.expected)
+            }
+            assertSameXML(t, tt.input, got)
+        })
+    }
+}
+
+// assertSameXML compara os tokens que encoding/xml lê dos dois documentos
+// (ignorando nós de texto só com whitespace).
+func assertSameXML(t *testing.T, want, got string) {
+    t.Helper()
+    w, err := xmlTokens(want)
+    if err != nil {
+        t.Fatalf("input inválido: %v", err)
+    }
+    g, err := xmlTokens(got)
+    if err != nil {
+        t.Fatalf("output inválido: %v (%q)", err, got)
+    }
+    if !reflect.DeepEqual(w, g) {
+        t.Errorf("documentos diferentes:\n%v\n%v", w, g)
+    }
+}
+
+func xmlTokens(s string) ([]xml.Token, error) {
+    var toks []xml.Token
+    d := xml.NewDecoder(strings.NewReader(s))
+    for {
+        tok, err := d.Token()
+        if err == io.EOF {
+            return toks, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        switch v := tok.(type) {
+        case xml.CharData:
+            if strings.TrimSpace(string(v)) == "" {
+                continue
+            }
+            tok = xml.CharData(strings.TrimSpace(string(v)))
+        case xml.Comment:
+            continue
+        }
+        toks = append(toks, xml.CopyToken(tok))
+    }
+}
@@ -0,0 +1,323 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: verificação (opcional) de XML bem formado: equilíbrio de tags,
+//          nomes das tags de fecho, atributos repetidos, caracteres legais e
+//          referências a entidades. Os erros indicam linha e coluna.
+// License: MIT
+
+package minifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// XMLSyntaxError descreve um problema de boa formação num documento XML.
+type XMLSyntaxError struct {
+    Offset int // posição em bytes no input
+    Line   int // linha (a partir de 1)
+    Column int // coluna em caracteres (a partir de 1)
+    Msg    string
+}
+
+func (e *XMLSyntaxError) Error() string {
+    return fmt.Sprintf("XML inválido (linha %d, coluna %d): %s", e.Line, e.Column, e.Msg)
+}
+
+// ValidateXML verifica se o input é XML bem formado. Devolve nil ou um
+// *XMLSyntaxError com a posição do primeiro problema encontrado.
+func ValidateXML(input string) error {
+    v := xmlValidator{input: input}
+    return v.run()
+}
+
+type xmlValidator struct {
+    input    string
+    entities map[string]bool // entidades declaradas no subconjunto interno do DOCTYPE
+    external bool            // DOCTYPE com DTD externo: entidades desconhecidas são aceites
+}
+
+func (v *xmlValidator) errorAt(offset int, format string, args ...any) error {
+    line, col := 1, 1
+    for _, r := range v.input[:offset] {
+        if r == '\n' {
+            line++
+            col = 1
+        } else {
+            col++
+        }
+    }
+    return &XMLSyntaxError{Offset: offset, Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (v *xmlValidator) run() error {
+    if err := v.checkChars(); err != nil {
+        return err
+    }
+
+    type openElem struct {
+        name string
+        pos  int
+    }
+    var stack []openElem
+    seenRoot := false
+
+    for _, t := range tokenizeMarkup(v.input, false) {
+        switch t.kind {
+        case markupText:
+            if k := strings.IndexByte(t.raw, '<'); k >= 0 {
+                return v.errorAt(t.pos+k, "'<' inesperado no texto")
+            }
+            if k := strings.Index(t.raw, "]]>"); k >= 0 {
+                return v.errorAt(t.pos+k, "']]>' não é permitido no texto")
+            }
+            if len(stack) == 0 && !isAllXMLWhitespace(t.raw) {
+                return v.errorAt(t.pos, "texto fora do elemento raiz")
+            }
+            if err := v.checkRefs(t.raw, t.pos); err != nil {
+                return err
+            }
+
+        case markupStart, markupEmpty:
+            if len(stack) == 0 && seenRoot {
+                return v.errorAt(t.pos, "mais do que um elemento raiz (<%s>)", t.name)
+            }
+            if err := v.checkTag(t); err != nil {
+                return err
+            }
+            seenRoot = true
+            if t.kind == markupStart {
+                stack = append(stack, openElem{name: t.name, pos: t.pos})
+            }
+
+        case markupEnd:
+            if !strings.HasSuffix(t.raw, ">") {
+                return v.errorAt(t.pos, "tag de fecho </%s> não terminada", t.name)
+            }
+            if !isXMLName(t.name) {
+                return v.errorAt(t.pos, "nome inválido na tag de fecho: %q", t.name)
+            }
+            if len(stack) == 0 {
+                return v.errorAt(t.pos, "tag de fecho </%s> sem abertura", t.name)
+            }
+            top := stack[len(stack)-1]
+            if top.name != t.name {
+                return v.errorAt(t.pos, "esperado </%s>, encontrado </%s>", top.name, t.name)
+            }
+            stack = stack[:len(stack)-1]
+
+        case markupComment:
+            if !strings.HasSuffix(t.raw, "-->") || len(t.raw) < 7 {
+                return v.errorAt(t.pos, "comentário não terminado")
+            }
+            if k := strings.Index(t.raw[4:len(t.raw)-3], "--"); k >= 0 {
+                return v.errorAt(t.pos+4+k, "'--' não é permitido dentro de comentários")
+            }
+            if strings.HasSuffix(t.raw, "--->") {
+                return v.errorAt(t.pos+len(t.raw)-4, "comentário não pode terminar em '-'")
+            }
+
+        case markupCDATA:
+            if !strings.HasSuffix(t.raw, "]]>") || len(t.raw) < 12 {
+                return v.errorAt(t.pos, "secção CDATA não terminada")
+            }
+            if len(stack) == 0 {
+                return v.errorAt(t.pos, "CDATA fora do elemento raiz")
+            }
+
+        case markupPI:
+            if !strings.HasSuffix(t.raw, "?>") || len(t.raw) < 4 {
+                return v.errorAt(t.pos, "processing instruction não terminada")
+            }
+            target := t.raw[2:]
+            if k := strings.IndexAny(target, " \t\r\n?"); k >= 0 {
+                target = target[:k]
+            }
+            if !isXMLName(target) {
+                return v.errorAt(t.pos, "processing instruction sem nome válido")
+            }
+            if strings.EqualFold(target, "xml") && t.pos != 0 {
+                return v.errorAt(t.pos, "a declaração <?xml ...?> tem de estar no início do documento")
+            }
+
+        case markupDecl:
+            if !strings.HasSuffix(t.raw, ">") {
+                return v.errorAt(t.pos, "declaração não terminada")
+            }
+            if !strings.HasPrefix(t.raw, "<!DOCTYPE") {
+                return v.errorAt(t.pos, "declaração desconhecida")
+            }
+            if seenRoot {
+                return v.errorAt(t.pos, "<!DOCTYPE> depois do elemento raiz")
+            }
+            v.readDoctype(t.raw)
+        }
+    }
+
+    if len(stack) > 0 {
+        top := stack[len(stack)-1]
+        return v.errorAt(top.pos, "elemento <%s> não fechado", top.name)
+    }
+    if !seenRoot {
+        return v.errorAt(len(v.input), "documento sem elemento raiz")
+    }
+    return nil
+}
+
+// checkTag valida nome e atributos de uma tag de abertura/vazia.
+func (v *xmlValidator) checkTag(t markupToken) error {
+    if !strings.HasSuffix(t.raw, ">") {
+        return v.errorAt(t.pos, "tag <%s> não terminada", t.name)
+    }
+    if !isXMLName(t.name) {
+        return v.errorAt(t.pos, "nome de elemento inválido: %q", t.name)
+    }
+
+    seen := make(map[string]bool, len(t.attrs))
+    for _, a := range t.attrs {
+        pos := t.pos + a.namePos
+        if !isXMLName(a.name) {
+            return v.errorAt(pos, "nome de atributo inválido: %q", a.name)
+        }
+        if a.noValue {
+            return v.errorAt(pos, "atributo %q sem valor", a.name)
+        }
+        if a.quote == 0 {
+            return v.errorAt(pos, "valor do atributo %q sem aspas", a.name)
+        }
+        if seen[a.name] {
+            return v.errorAt(pos, "atributo %q repetido em <%s>", a.name, t.name)
+        }
+        seen[a.name] = true
+        if k := strings.IndexByte(a.value, '<'); k >= 0 {
+            return v.errorAt(pos, "'<' não é permitido no valor do atributo %q", a.name)
+        }
+        if err := v.checkRefs(a.value, t.pos+a.valuePos); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// checkRefs valida as referências &nome; e &#...; num texto.
+func (v *xmlValidator) checkRefs(s string, base int) error {
+    for i := 0; i < len(s); i++ {
+        if s[i] != '&' {
+            continue
+        }
+        end := strings.IndexByte(s[i:], ';')
+        if end < 0 {
+            return v.errorAt(base+i, "'&' sem referência terminada em ';'")
+        }
+        ref := s[i+1 : i+end]
+        switch {
+        case strings.HasPrefix(ref, "#"):
+            if _, ok := parseCharRef(ref[1:]); !ok {
+                return v.errorAt(base+i, "referência de carácter inválida: &%s;", ref)
+            }
+        case !isXMLName(ref):
+            return v.errorAt(base+i, "referência a entidade inválida: &%s;", ref)
+        case !v.knownEntity(ref):
+            return v.errorAt(base+i, "entidade não declarada: &%s;", ref)
+        }
+        i += end
+    }
+    return nil
+}
+
+func (v *xmlValidator) knownEntity(name string) bool {
+    switch name {
+    case "lt", "gt", "amp", "apos", "quot":
+        return true
+    }
+    return v.external || v.entities[name]
+}
+
+// readDoctype recolhe as entidades declaradas no DOCTYPE.
+func (v *xmlValidator) readDoctype(decl string) {
+    subset := ""
+    head := decl
+    if k := strings.IndexByte(decl, '['); k >= 0 {
+        head = decl[:k]
+        subset = decl[k:]
+    }
+    if strings.Contains(head, "SYSTEM") || strings.Contains(head, "PUBLIC") {
+        v.external = true
+    }
+    v.entities = map[string]bool{}
+    for {
+        k := strings.Index(subset, "<!ENTITY")
+        if k < 0 {
+            return
+        }
+        fields := strings.Fields(subset[k+len("<!ENTITY"):])
+        if len(fields) > 0 && fields[0] == "%" {
+            // entidade de parâmetro: só conta dentro do DTD
+            fields = fields[1:]
+        } else if len(fields) > 0 {
+            v.entities[fields[0]] = true
+        }
+        subset = subset[k+len("<!ENTITY"):]
+    }
+}
+
+// checkChars garante que o input é UTF-8 válido e só usa caracteres XML legais.
+func (v *xmlValidator) checkChars() error {
+    for i := 0; i < len(v.input); {
+        r, size := utf8.DecodeRuneInString(v.input[i:])
+        if r == utf8.RuneError && size <= 1 {
+            return v.errorAt(i, "UTF-8 inválido")
+        }
+        if !isXMLChar(r) {
+            return v.errorAt(i, "carácter ilegal em XML: %U", r)
+        }
+        i += size
+    }
+    return nil
+}
+
+// isXMLChar segue a produção Char do XML 1.0.
+func isXMLChar(r rune) bool {
+    return r == 0x09 || r == 0x0A || r == 0x0D ||
+        (r >= 0x20 && r <= 0xD7FF) ||
+        (r >= 0xE000 && r <= 0xFFFD) ||
+        (r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// parseCharRef interpreta o que vem depois de "&#" (ex: "233" ou "xE9").
+func parseCharRef(s string) (rune, bool) {
+    base := 10
+    if strings.HasPrefix(s, "x") {
+        base = 16
+        s = s[1:]
+    }
+    if s == "" || strings.ContainsAny(s, "+-") {
+        return 0, false
+    }
+    n, err := strconv.ParseUint(s, base, 32)
+    if err != nil || !isXMLChar(rune(n)) {
+        return 0, false
+    }
+    return rune(n), true
+}
+
+// isXMLName é uma verificação simplificada da produção Name
+// (ASCII estrito; qualquer caractere não-ASCII é aceite).
+func isXMLName(s string) bool {
+    if s == "" {
+        return false
+    }
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if isMarkupNameStart(c) {
+            continue
+        }
+        if i > 0 && ((c >= '0' && c <= '9') || c == '-' || c == '.') {
+            continue
+        }
+        return false
+    }
+    return true
+}
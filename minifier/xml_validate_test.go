@@ -0,0 +1,67 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para a verificação de XML bem formado
+// License: MIT
+
+package minifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateXML(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        line  int // 0 = esperado válido
+        col   int
+    }{
+        {"Valid document", "<?xml version=\"1.0\"?>\n<root a=\"1\"><b>x &amp; &#233;</b><c/></root>", 0, 0},
+        {"Declared entity", `<!DOCTYPE r [<!ENTITY e "x">]><r>&e;</r>`, 0, 0},
+        {"Mismatched end tag", "<root>\n  <a></b>\n</root>", 2, 6},
+        {"Unclosed element", "<root>\n<a>", 2, 1},
+        {"End tag without start", "<root></root></x>", 1, 14},
+        {"Duplicate attribute", `<root a="1" a="2"/>`, 1, 13},
+        {"Bad ref in attribute", `<root ab="&amp;" b="x &q;"/>`, 1, 23},
+        {"Unquoted attribute", `<root a=1/>`, 1, 7},
+        {"Undeclared entity", "<root>&nbsp;</root>", 1, 7},
+        {"Bad char ref", "<root>&#0;</root>", 1, 7},
+        {"Illegal char", "<root>\x01</root>", 1, 7},
+        {"Two roots", "<a/><b/>", 1, 5},
+        {"Text outside root", "<a/>x", 1, 5},
+        {"Double dash in comment", "<a><!-- x -- y --></a>", 1, 11},
+        {"No root", "<!-- só comentário -->", 1, 23},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := ValidateXML(tt.input)
+            if tt.line == 0 {
+                if err != nil {
+                    t.Fatalf("esperado válido, obtido %v", err)
+                }
+                return
+            }
+            var se *XMLSyntaxError
+            if !errors.As(err, &se) {
+                t.Fatalf("esperado *XMLSyntaxError, obtido %v", err)
+            }
+            if se.Line != tt.line || se.Column != tt.col {
+                t.Errorf("posição %d:%d, esperado %d:%d (%s)", se.Line, se.Column, tt.line, tt.col, se.Msg)
+            }
+        })
+    }
+}
+
+func TestMinifyXMLValidateOption(t *testing.T) {
+    opts := DefaultOptions()
+    opts.XMLValidate = true
+    if _, err := Minify("<a><b></a>", XML, opts); err == nil {
+        t.Error("esperado erro com XMLValidate")
+    }
+    out, err := Minify("<a>\n  <b/>\n</a>", XML, opts)
+    if err != nil || out != "<a><b/></a>" {
+        t.Errorf("got %q, %v", out, err)
+    }
+}
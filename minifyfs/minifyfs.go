@@ -0,0 +1,243 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: fs.FS que devolve os ficheiros de outro fs.FS já minificados (ex: um
+//          embed.FS servido com http.FileServer(http.FS(...))). O tipo de cada
+//          ficheiro vem da extensão (minifier.Registry); os outros ficheiros e as
+//          diretorias passam tal como estão.
+//          - o resultado fica em cache por ficheiro, até mudar a data de
+//            modificação ou o tamanho do original
+//          - Stat, ReadDir e os fs.FileInfo devolvem o tamanho minificado
+//          - os ficheiros abertos implementam io.Seeker e io.ReaderAt, como o
+//            http.FileServer precisa para pedidos parciais
+// License: MIT
+
+package minifyfs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// FS é um fs.FS com os ficheiros de outro fs.FS minificados. Pode ser usado por
+// várias goroutines em simultâneo.
+type FS struct {
+    fsys fs.FS
+    reg  *minifier.Registry
+
+    mu    sync.RWMutex
+    cache map[string]*cacheEntry
+}
+
+// cacheEntry é o resultado minificado de um ficheiro, válido enquanto o original
+// tiver a mesma data de modificação e o mesmo tamanho.
+type cacheEntry struct {
+    modTime time.Time
+    size    int64
+    data    []byte
+}
+
+// New devolve fsys com os ficheiros minificados com opts (nil =
+// minifier.DefaultOptions()). Os tipos suportados são os de opts.Registry;
+// sem registo próprio usa os minificadores da biblioteca.
+func New(fsys fs.FS, opts *minifier.Options) *FS {
+    if opts == nil {
+        opts = minifier.DefaultOptions()
+    }
+    reg := opts.Registry
+    if reg == nil {
+        reg = minifier.NewRegistry(opts)
+    }
+    return &FS{
+        fsys:  fsys,
+        reg:   reg,
+        cache: make(map[string]*cacheEntry),
+    }
+}
+
+// Open abre o ficheiro name; se for de um tipo suportado, o conteúdo é o
+// minificado.
+func (f *FS) Open(name string) (fs.File, error) {
+    file, err := f.fsys.Open(name)
+    if err != nil {
+        return nil, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+    if info.IsDir() {
+        if d, ok := file.(fs.ReadDirFile); ok {
+            return &dirFile{ReadDirFile: d, fs: f, name: name}, nil
+        }
+        return file, nil
+    }
+    mimeType := f.mimeType(name, info)
+    if mimeType == "" {
+        return file, nil
+    }
+    data, err := f.minified(name, mimeType, info, file)
+    file.Close()
+    if err != nil {
+        return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+    }
+    return &memFile{
+        Reader: bytes.NewReader(data),
+        info:   fileInfo{FileInfo: info, size: int64(len(data))},
+    }, nil
+}
+
+// ReadFile implementa fs.ReadFileFS.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+    file, err := f.Open(name)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+    if m, ok := file.(*memFile); ok {
+        b := make([]byte, m.Len())
+        m.Read(b)
+        return b, nil
+    }
+    if _, ok := file.(fs.ReadDirFile); ok {
+        return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+    }
+    return io.ReadAll(file)
+}
+
+// Stat implementa fs.StatFS (com o tamanho minificado).
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+    info, err := fs.Stat(f.fsys, name)
+    if err != nil {
+        return nil, err
+    }
+    return f.wrapInfo(name, info)
+}
+
+// ReadDir implementa fs.ReadDirFS (com o tamanho minificado em Info()).
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+    entries, err := fs.ReadDir(f.fsys, name)
+    return f.wrapEntries(name, entries), err
+}
+
+// mimeType devolve o MIME type com que o ficheiro é minificado ("" = não é).
+func (f *FS) mimeType(name string, info fs.FileInfo) string {
+    if !info.Mode().IsRegular() {
+        return ""
+    }
+    mimeType := f.reg.MIMEType(name)
+    if mimeType == "" {
+        return ""
+    }
+    if _, ok := f.reg.Lookup(mimeType); !ok {
+        return ""
+    }
+    return mimeType
+}
+
+// minified devolve o conteúdo minificado de name, da cache ou lido de r. Se o
+// minificador der erro, o conteúdo fica como está.
+func (f *FS) minified(name, mimeType string, info fs.FileInfo, r io.Reader) ([]byte, error) {
+    f.mu.RLock()
+    e := f.cache[name]
+    f.mu.RUnlock()
+    if e != nil && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+        return e.data, nil
+    }
+
+    if r == nil {
+        file, err := f.fsys.Open(name)
+        if err != nil {
+            return nil, err
+        }
+        defer file.Close()
+        r = file
+    }
+    src, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    var out bytes.Buffer
+    data := src
+    if err := f.reg.Minify(mimeType, &out, bytes.NewReader(src), nil); err == nil {
+        data = out.Bytes()
+    }
+
+    f.mu.Lock()
+    f.cache[name] = &cacheEntry{modTime: info.ModTime(), size: info.Size(), data: data}
+    f.mu.Unlock()
+    return data, nil
+}
+
+// wrapInfo troca o tamanho de info pelo do ficheiro minificado.
+func (f *FS) wrapInfo(name string, info fs.FileInfo) (fs.FileInfo, error) {
+    mimeType := f.mimeType(name, info)
+    if mimeType == "" {
+        return info, nil
+    }
+    data, err := f.minified(name, mimeType, info, nil)
+    if err != nil {
+        return nil, err
+    }
+    return fileInfo{FileInfo: info, size: int64(len(data))}, nil
+}
+
+func (f *FS) wrapEntries(dir string, entries []fs.DirEntry) []fs.DirEntry {
+    for i, e := range entries {
+        if !e.IsDir() && f.reg.MIMEType(e.Name()) != "" {
+            entries[i] = dirEntry{DirEntry: e, fs: f, name: path.Join(dir, e.Name())}
+        }
+    }
+    return entries
+}
+
+// fileInfo é o fs.FileInfo do original com o tamanho minificado.
+type fileInfo struct {
+    fs.FileInfo
+    size int64
+}
+
+func (i fileInfo) Size() int64 { return i.size }
+
+// dirEntry calcula Info() com o tamanho minificado (só quando é pedido).
+type dirEntry struct {
+    fs.DirEntry
+    fs   *FS
+    name string
+}
+
+func (e dirEntry) Info() (fs.FileInfo, error) {
+    info, err := e.DirEntry.Info()
+    if err != nil {
+        return nil, err
+    }
+    return e.fs.wrapInfo(e.name, info)
+}
+
+// dirFile é uma diretoria aberta cujas entradas têm o tamanho minificado.
+type dirFile struct {
+    fs.ReadDirFile
+    fs   *FS
+    name string
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+    entries, err := d.ReadDirFile.ReadDir(n)
+    return d.fs.wrapEntries(d.name, entries), err
+}
+
+// memFile é um ficheiro minificado aberto.
+type memFile struct {
+    *bytes.Reader
+    info fs.FileInfo
+}
+
+func (m *memFile) Stat() (fs.FileInfo, error) { return m.info, nil }
+
+func (m *memFile) Close() error { return nil }
@@ -0,0 +1,138 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o fs.FS com assets minificados
+// License: MIT
+
+package minifyfs
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+func testFS() fstest.MapFS {
+    return fstest.MapFS{
+        "index.html":       {Data: []byte("<html>\n  <body>\n    <p>Olá   mundo</p>\n  </body>\n</html>\n")},
+        "css/site.css":     {Data: []byte("body {\n  color: red;\n}\n")},
+        "js/app.js":        {Data: []byte("var a = 1;\n")},
+        "data/config.json": {Data: []byte(`{ "a": [1, 2] }`)},
+        "img/logo.png":     {Data: []byte("\x89PNG  \n")},
+        "LEIAME.txt":       {Data: []byte("texto   com espaços\n")},
+    }
+}
+
+func TestFSConformance(t *testing.T) {
+    fsys := New(testFS(), nil)
+    if err := fstest.TestFS(fsys, "index.html", "css/site.css", "js/app.js", "data/config.json", "img/logo.png", "LEIAME.txt"); err != nil {
+        t.Fatal(err)
+    }
+
+    sub, err := fs.Sub(fsys, "css")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := fstest.TestFS(sub, "site.css"); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestFSContent(t *testing.T) {
+    fsys := New(testFS(), nil)
+    tests := []struct {
+        name     string
+        expected string
+    }{
+        {"index.html", "<html><body><p>Olá mundo</p></body></html>"},
+        {"css/site.css", "body{color:red;}"},
+        {"js/app.js", "var a=1;"},
+        {"data/config.json", `{"a":[1,2]}`},
+        {"img/logo.png", "\x89PNG  \n"},
+        {"LEIAME.txt", "texto   com espaços\n"},
+    }
+    for _, tt := range tests {
+        b, err := fs.ReadFile(fsys, tt.name)
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if string(b) != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.name, b, tt.expected)
+        }
+
+        info, err := fs.Stat(fsys, tt.name)
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if info.Size() != int64(len(tt.expected)) {
+            t.Errorf("%s: Size() = %d, want %d", tt.name, info.Size(), len(tt.expected))
+        }
+    }
+}
+
+func TestFSCache(t *testing.T) {
+    m := testFS()
+    fsys := New(m, nil)
+
+    b, _ := fs.ReadFile(fsys, "css/site.css")
+    if string(b) != "body{color:red;}" {
+        t.Fatalf("got %q", b)
+    }
+
+    // mesma data e tamanho: continua a vir da cache
+    m["css/site.css"].Data = []byte("p {\n  color: blue;\n}\n\n\n")
+    b, _ = fs.ReadFile(fsys, "css/site.css")
+    if string(b) != "body{color:red;}" {
+        t.Errorf("cache: got %q", b)
+    }
+
+    // o original mudou: é minificado outra vez
+    m["css/site.css"].ModTime = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+    b, _ = fs.ReadFile(fsys, "css/site.css")
+    if string(b) != "p{color:blue;}" {
+        t.Errorf("depois de mudar: got %q", b)
+    }
+}
+
+func TestFSFileServer(t *testing.T) {
+    srv := http.FileServer(http.FS(New(testFS(), nil)))
+
+    req := httptest.NewRequest("GET", "/css/site.css", nil)
+    rec := httptest.NewRecorder()
+    srv.ServeHTTP(rec, req)
+    if rec.Body.String() != "body{color:red;}" {
+        t.Errorf("got %q", rec.Body.String())
+    }
+    if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len("body{color:red;}")) {
+        t.Errorf("Content-Length = %q", got)
+    }
+
+    // pedido parcial sobre o conteúdo minificado
+    req = httptest.NewRequest("GET", "/css/site.css", nil)
+    req.Header.Set("Range", "bytes=0-3")
+    rec = httptest.NewRecorder()
+    srv.ServeHTTP(rec, req)
+    if rec.Code != http.StatusPartialContent || rec.Body.String() != "body" {
+        t.Errorf("Range: got %d %q", rec.Code, rec.Body.String())
+    }
+}
+
+func TestFSRegistry(t *testing.T) {
+    opts := minifier.DefaultOptions()
+    opts.Registry = minifier.NewRegistry(opts)
+    opts.Registry.Register("text/plain", minifier.MinifierFunc(func(dst io.Writer, src io.Reader, _ map[string]string) error {
+        _, err := io.WriteString(dst, "min")
+        return err
+    }), ".txt")
+
+    b, err := fs.ReadFile(New(testFS(), opts), "LEIAME.txt")
+    if err != nil || string(b) != "min" {
+        t.Errorf("got %q, %v", b, err)
+    }
+}
@@ -0,0 +1,315 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: middleware net/http que minifica as respostas dos handlers (HTML, CSS,
+//          JS, JSON, XML, SVG ...) sem passo de build. O minificador é escolhido
+//          pelo Content-Type da resposta (ou pelo tipo detetado nos primeiros
+//          bytes, como faz o net/http), através do minifier.Registry.
+//          - o corpo é guardado em memória e minificado no fim: o Content-Length
+//            passa a ser o do resultado minificado (também em pedidos HEAD)
+//          - Flush envia o que já foi escrito, minificado como um bloco
+//            independente (a partir daí a resposta segue sem Content-Length)
+//          - respostas já comprimidas (Content-Encoding), parciais (206),
+//            204/304 e de tipos sem minificador passam sem alterações
+//          - funciona com middleware de gzip por fora (recebe o corpo minificado)
+//            ou por dentro (a resposta já vem com Content-Encoding e passa)
+// License: MIT
+
+package minifyhttp
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+// Handler devolve um http.Handler que minifica as respostas de next com opts
+// (nil = minifier.DefaultOptions()). Os tipos suportados são os de
+// opts.Registry; sem registo próprio usa os minificadores da biblioteca.
+func Handler(next http.Handler, opts *minifier.Options) http.Handler {
+    if opts == nil {
+        opts = minifier.DefaultOptions()
+    }
+    reg := opts.Registry
+    if reg == nil {
+        reg = minifier.NewRegistry(opts)
+    }
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mw := &responseWriter{
+            ResponseWriter: w,
+            reg:            reg,
+            head:           r.Method == http.MethodHead,
+            outerEnc:       w.Header().Get("Content-Encoding"),
+        }
+        defer mw.release()
+        next.ServeHTTP(mw, r)
+        mw.finish()
+    })
+}
+
+// Middleware é Handler na forma usada pelos routers: func(http.Handler) http.Handler.
+func Middleware(opts *minifier.Options) func(http.Handler) http.Handler {
+    return func(next http.Handler) http.Handler {
+        return Handler(next, opts)
+    }
+}
+
+// estados de responseWriter
+const (
+    stateUndecided = iota // sem Content-Type: decide no primeiro Write
+    statePass             // escreve diretamente na resposta original
+    stateMinify           // guarda o corpo em buf para minificar
+)
+
+var bufPool = sync.Pool{
+    New: func() any { return new(bytes.Buffer) },
+}
+
+// responseWriter guarda o corpo das respostas a minificar e adia o WriteHeader
+// até saber o tamanho final.
+type responseWriter struct {
+    http.ResponseWriter
+    reg  *minifier.Registry
+    head bool // pedido HEAD
+    // Content-Encoding posto por um middleware de fora antes deste (ex: gzip
+    // que comprime tudo o que lhe escrevem): não quer dizer que o corpo já
+    // venha comprimido do handler
+    outerEnc string
+
+    status  int // 0 = WriteHeader ainda não foi chamado
+    state   int
+    mime    string
+    buf     *bytes.Buffer // corpo ainda por minificar
+    flushed bool          // já foi enviado um bloco (cabeçalhos escritos)
+    endSpace bool         // o último bloco enviado acabou em espaço
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+    if code >= 100 && code < 200 {
+        // respostas informativas (ex: 103 Early Hints) não têm corpo
+        w.ResponseWriter.WriteHeader(code)
+        return
+    }
+    if w.status != 0 {
+        return
+    }
+    w.status = code
+    h := w.Header()
+    if enc := h.Get("Content-Encoding"); enc != "" && enc != w.outerEnc && !strings.EqualFold(enc, "identity") {
+        w.pass()
+        return
+    }
+    switch code {
+    case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+        w.pass()
+        return
+    }
+    if ct := h.Get("Content-Type"); ct != "" {
+        w.decide(ct)
+    }
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+    if w.status == 0 {
+        w.WriteHeader(http.StatusOK)
+    }
+    if w.state == stateUndecided {
+        if len(p) == 0 {
+            return 0, nil
+        }
+        ct := http.DetectContentType(p)
+        w.Header().Set("Content-Type", ct)
+        w.decide(ct)
+    }
+    if w.state == statePass {
+        return w.ResponseWriter.Write(p)
+    }
+    return w.buf.Write(p)
+}
+
+// Flush envia o corpo guardado até agora, minificado como um bloco à parte.
+// Os blocos devem terminar em fronteiras onde o conteúdo é independente (entre
+// elementos, regras ou registos): um Flush a meio de uma string ou de um <pre>
+// faz com que cada metade seja minificada sem conhecer a outra.
+func (w *responseWriter) Flush() {
+    if w.status == 0 {
+        w.WriteHeader(http.StatusOK)
+    }
+    switch w.state {
+    case stateUndecided:
+        w.pass()
+    case stateMinify:
+        w.writeChunk()
+    }
+    if f, ok := w.ResponseWriter.(http.Flusher); ok {
+        f.Flush()
+    }
+}
+
+// Unwrap permite usar http.ResponseController com a resposta original.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+    return w.ResponseWriter
+}
+
+// decide escolhe entre minificar e deixar passar, conforme o Content-Type.
+func (w *responseWriter) decide(ct string) {
+    if _, ok := w.reg.Lookup(ct); !ok {
+        w.pass()
+        return
+    }
+    w.state = stateMinify
+    w.mime = ct
+    w.buf = bufPool.Get().(*bytes.Buffer)
+    w.buf.Reset()
+}
+
+func (w *responseWriter) pass() {
+    w.state = statePass
+    w.ResponseWriter.WriteHeader(w.status)
+}
+
+// writeChunk minifica e envia o conteúdo de buf (usado por Flush).
+func (w *responseWriter) writeChunk() {
+    if !w.flushed {
+        w.Header().Del("Content-Length")
+        w.writeHeader()
+        w.flushed = true
+    }
+    if w.buf.Len() == 0 {
+        return
+    }
+    out := bufPool.Get().(*bytes.Buffer)
+    defer bufPool.Put(out)
+    out.Reset()
+    w.minify(out, w.buf.Bytes())
+    keepEdgeSpace(out, w.buf.Bytes(), w.endSpace)
+    w.buf.Reset()
+    if out.Len() > 0 {
+        w.endSpace = isSpace(out.Bytes()[out.Len()-1])
+    }
+    w.ResponseWriter.Write(out.Bytes())
+}
+
+// finish é chamado quando o handler termina: minifica o resto do corpo.
+func (w *responseWriter) finish() {
+    if w.status == 0 {
+        if !w.head {
+            return // nada escrito: o net/http responde 200 sem corpo
+        }
+        // HEAD só com cabeçalhos: é preciso corrigir o Content-Length
+        w.WriteHeader(http.StatusOK)
+    }
+    switch {
+    case w.state == stateUndecided:
+        w.pass()
+    case w.flushed:
+        w.writeChunk()
+    case w.state == stateMinify:
+        h := w.Header()
+        if w.head && w.buf.Len() == 0 {
+            // HEAD sem corpo: o Content-Length do handler é o do original
+            h.Del("Content-Length")
+            w.writeHeader()
+            return
+        }
+        out := bufPool.Get().(*bytes.Buffer)
+        defer bufPool.Put(out)
+        out.Reset()
+        w.minify(out, w.buf.Bytes())
+        h.Set("Content-Length", strconv.Itoa(out.Len()))
+        w.writeHeader()
+        w.ResponseWriter.Write(out.Bytes())
+    }
+}
+
+// minify escreve src minificado em out; se o minificador der erro, src fica
+// como está (tal como o conteúdo embebido em HTML).
+func (w *responseWriter) minify(out *bytes.Buffer, src []byte) {
+    if err := w.reg.Minify(w.mime, out, bytes.NewReader(src), nil); err != nil {
+        out.Reset()
+        out.Write(src)
+    }
+}
+
+// writeHeader envia os cabeçalhos de uma resposta minificada: o ETag do handler
+// passa a fraco (o corpo já não é byte a byte o mesmo) e os pedidos parciais
+// deixam de ser anunciados (os intervalos seriam do original).
+func (w *responseWriter) writeHeader() {
+    h := w.Header()
+    if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+        h.Set("ETag", "W/"+etag)
+    }
+    h.Del("Accept-Ranges")
+    w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *responseWriter) release() {
+    if w.buf != nil {
+        bufPool.Put(w.buf)
+        w.buf = nil
+    }
+}
+
+// keepEdgeSpace repõe o espaço no início/fim de um bloco que o minificador
+// cortou por estar na "ponta do documento": entre dois blocos pode ser
+// significativo ("Olá " + "mundo"). Uma quebra de linha fica quebra de linha
+// (em JS pode terminar uma instrução). afterSpace indica que o bloco anterior
+// já acabou em espaço.
+func keepEdgeSpace(out *bytes.Buffer, src []byte, afterSpace bool) {
+    lead := edgeSpace(src, true)
+    if afterSpace {
+        lead = 0
+    }
+    trail := edgeSpace(src, false)
+    if lead == 0 && trail == 0 {
+        return
+    }
+    b := out.Bytes()
+    if len(b) == 0 {
+        // bloco só com espaços
+        if afterSpace && trail != '\n' {
+            return
+        }
+        if lead == '\n' || trail == '\n' {
+            out.WriteByte('\n')
+        } else {
+            out.WriteByte(' ')
+        }
+        return
+    }
+    if lead != 0 && !isSpace(b[0]) {
+        tmp := append([]byte{lead}, b...)
+        out.Reset()
+        out.Write(tmp)
+        b = out.Bytes()
+    }
+    if trail != 0 && !isSpace(b[len(b)-1]) {
+        out.WriteByte(trail)
+    }
+}
+
+// edgeSpace devolve ' ' ou '\n' se src começa (lead) ou acaba em espaços, 0 se não.
+func edgeSpace(src []byte, lead bool) byte {
+    c := byte(0)
+    for k := range src {
+        i := k
+        if !lead {
+            i = len(src) - 1 - k
+        }
+        if !isSpace(src[i]) {
+            break
+        }
+        if src[i] == '\n' {
+            return '\n'
+        }
+        c = ' '
+    }
+    return c
+}
+
+func isSpace(c byte) bool {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,241 @@
+// Author: João Pinto
+// Date: 2026-10-18
+// Purpose: teste unitário para o middleware HTTP de minificação
+// License: MIT
+
+package minifyhttp
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinjoa/minifyx/minifier"
+)
+
+const page = "<html>\n  <body>\n    <p>Olá   mundo</p>\n  </body>\n</html>\n"
+
+func serve(h http.Handler, method, target string, header ...string) *httptest.ResponseRecorder {
+    req := httptest.NewRequest(method, target, nil)
+    for i := 0; i+1 < len(header); i += 2 {
+        req.Header.Set(header[i], header[i+1])
+    }
+    rec := httptest.NewRecorder()
+    h.ServeHTTP(rec, req)
+    return rec
+}
+
+func TestMinifyByContentType(t *testing.T) {
+    tests := []struct {
+        name     string
+        ct       string
+        body     string
+        expected string
+    }{
+        {"html", "text/html; charset=utf-8", page, minifier.MinifyHTML(page, nil)},
+        {"json", "application/json", `{ "a": [1, 2] }`, `{"a":[1,2]}`},
+        {"css", "text/css", "body {\n  color: red;\n}", "body{color:red;}"},
+        {"sniffed html", "", page, minifier.MinifyHTML(page, nil)},
+        {"texto", "text/plain", "a   b\n", "a   b\n"},
+        {"imagem", "image/png", "\x89PNG  \n", "\x89PNG  \n"},
+    }
+    for _, tt := range tests {
+        h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            if tt.ct != "" {
+                w.Header().Set("Content-Type", tt.ct)
+            }
+            w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+            io.WriteString(w, tt.body)
+        }), nil)
+        rec := serve(h, "GET", "/")
+        if got := rec.Body.String(); got != tt.expected {
+            t.Errorf("%s: got %q, want %q", tt.name, got, tt.expected)
+        }
+        if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(tt.expected)) {
+            t.Errorf("%s: Content-Length = %q, want %d", tt.name, got, len(tt.expected))
+        }
+    }
+}
+
+func TestPassThroughStatus(t *testing.T) {
+    for _, code := range []int{http.StatusNoContent, http.StatusNotModified} {
+        h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            w.Header().Set("Content-Type", "text/html")
+            w.WriteHeader(code)
+        }), nil)
+        rec := serve(h, "GET", "/")
+        if rec.Code != code || rec.Body.Len() != 0 {
+            t.Errorf("%d: got %d %q", code, rec.Code, rec.Body.String())
+        }
+    }
+
+    // nada escrito: fica o 200 por omissão
+    rec := serve(Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), nil), "GET", "/")
+    if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+        t.Errorf("vazio: got %d %q", rec.Code, rec.Body.String())
+    }
+}
+
+func TestServeContent(t *testing.T) {
+    h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("ETag", `"v1"`)
+        http.ServeContent(w, r, "index.html", time.Time{}, strings.NewReader(page))
+    }), nil)
+    min := minifier.MinifyHTML(page, nil)
+
+    rec := serve(h, "GET", "/")
+    if rec.Body.String() != min {
+        t.Errorf("GET: got %q, want %q", rec.Body.String(), min)
+    }
+    if got := rec.Header().Get("ETag"); got != `W/"v1"` {
+        t.Errorf("ETag = %q, want fraco", got)
+    }
+    if got := rec.Header().Get("Accept-Ranges"); got != "" {
+        t.Errorf("Accept-Ranges = %q, want vazio", got)
+    }
+
+    // HEAD: o ServeContent não escreve o corpo, por isso o tamanho do
+    // original não pode ficar
+    rec = serve(h, "HEAD", "/")
+    if got := rec.Header().Get("Content-Length"); got != "" {
+        t.Errorf("HEAD: Content-Length = %q, want removido", got)
+    }
+
+    // pedido parcial: os intervalos são do original, por isso não se mexe
+    rec = serve(h, "GET", "/", "Range", "bytes=0-5")
+    if rec.Code != http.StatusPartialContent || rec.Body.String() != page[:6] {
+        t.Errorf("Range: got %d %q", rec.Code, rec.Body.String())
+    }
+}
+
+func TestHead(t *testing.T) {
+    h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+        if r.URL.Path == "/corpo" || r.Method != http.MethodHead {
+            io.WriteString(w, page)
+        }
+    }), nil)
+    min := minifier.MinifyHTML(page, nil)
+
+    // o handler escreve o corpo também em HEAD: o tamanho é o mesmo do GET
+    rec := serve(h, "HEAD", "/corpo")
+    if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(min)) {
+        t.Errorf("HEAD com corpo: Content-Length = %q, want %d", got, len(min))
+    }
+
+    // só cabeçalhos: o tamanho minificado não é conhecido
+    rec = serve(h, "HEAD", "/")
+    if got := rec.Header().Get("Content-Length"); got != "" {
+        t.Errorf("HEAD sem corpo: Content-Length = %q, want removido", got)
+    }
+}
+
+func TestFlush(t *testing.T) {
+    h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        w.Header().Set("Content-Length", "999")
+        io.WriteString(w, "<ul>\n  <li>Olá ")
+        w.(http.Flusher).Flush()
+        io.WriteString(w, "mundo</li>\n")
+        http.NewResponseController(w).Flush()
+        io.WriteString(w, "  <li>b</li>\n</ul>\n")
+    }), nil)
+    rec := serve(h, "GET", "/")
+    if !rec.Flushed {
+        t.Error("Flush não chegou à resposta original")
+    }
+    expected := "<ul><li>Olá mundo</li>\n<li>b</li></ul>\n"
+    if got := rec.Body.String(); got != expected {
+        t.Errorf("got %q, want %q", got, expected)
+    }
+    if got := rec.Header().Get("Content-Length"); got != "" {
+        t.Errorf("Content-Length = %q, want removido", got)
+    }
+}
+
+// gzipHandler é um middleware de gzip mínimo, só para os testes.
+func gzipHandler(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Encoding", "gzip")
+        w.Header().Del("Content-Length")
+        gz := gzip.NewWriter(w)
+        defer gz.Close()
+        next.ServeHTTP(gzipWriter{w, gz}, r)
+    })
+}
+
+type gzipWriter struct {
+    http.ResponseWriter
+    gz *gzip.Writer
+}
+
+func (g gzipWriter) Write(p []byte) (int, error) { return g.gz.Write(p) }
+
+func (g gzipWriter) WriteHeader(code int) {
+    g.Header().Del("Content-Length")
+    g.ResponseWriter.WriteHeader(code)
+}
+
+func gunzip(t *testing.T, b []byte) string {
+    t.Helper()
+    zr, err := gzip.NewReader(strings.NewReader(string(b)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    out, err := io.ReadAll(zr)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return string(out)
+}
+
+func TestGzipComposition(t *testing.T) {
+    app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        io.WriteString(w, page)
+    })
+    min := minifier.MinifyHTML(page, nil)
+
+    // gzip por fora: comprime o HTML já minificado
+    rec := serve(gzipHandler(Handler(app, nil)), "GET", "/")
+    if got := gunzip(t, rec.Body.Bytes()); got != min {
+        t.Errorf("gzip(minify): got %q, want %q", got, min)
+    }
+    if got := rec.Header().Get("Content-Length"); got != "" {
+        t.Errorf("gzip(minify): Content-Length = %q, want removido", got)
+    }
+
+    // gzip por dentro: a resposta já vem comprimida e passa intacta
+    rec = serve(Handler(gzipHandler(app), nil), "GET", "/")
+    if got := gunzip(t, rec.Body.Bytes()); got != page {
+        t.Errorf("minify(gzip): got %q, want %q", got, page)
+    }
+}
+
+func TestMiddlewareRegistry(t *testing.T) {
+    opts := minifier.DefaultOptions()
+    opts.Registry = minifier.NewRegistry(opts)
+    opts.Registry.Register("text/plain", minifier.MinifierFunc(func(dst io.Writer, src io.Reader, _ map[string]string) error {
+        b, err := io.ReadAll(src)
+        if err != nil {
+            return err
+        }
+        _, err = io.WriteString(dst, strings.Join(strings.Fields(string(b)), " "))
+        return err
+    }))
+
+    h := Middleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+        io.WriteString(w, "a   b\n  c")
+    }))
+    rec := serve(h, "GET", "/")
+    if got := rec.Body.String(); got != "a b c" {
+        t.Errorf("got %q, want %q", got, "a b c")
+    }
+}